@@ -0,0 +1,156 @@
+// blocos.go - Blocos empurráveis, placas de pressão e reinício do quebra-cabeça
+package main
+
+// Evento produzido quando o personagem empurra um bloco
+const EventBlocoEmpurrado = "BlocoEmpurrado"
+
+type BlocoEmpurradoData struct {
+	De, Para Position
+}
+
+// Informa se há um bloco no tile, esteja ele sobre uma placa ou não
+func jogoEhBloco(jogo *Jogo, x, y int) bool {
+	elem, ok := jogoElementoEm(jogo, x, y)
+	return ok && (elem == Bloco || elem == BlocoSobrePlaca)
+}
+
+// Empurra o bloco em (x, y) uma casa na direção (dx, dy). O bloco só vai para
+// um tile vazio ou uma placa, sem o personagem ou o monstro.
+func jogoEmpurrarBloco(jogo *Jogo, x, y, dx, dy int) bool {
+	nx, ny := x+dx, y+dy
+	destino, ok := jogoElementoEm(jogo, nx, ny)
+	if !ok || jogoPosicaoOcupada(jogo, nx, ny) || (destino != Vazio && destino != PlacaPressao) {
+		return false
+	}
+
+	// O bloco deixa para trás a placa sobre a qual estava, se houver
+	if jogo.Mapa[y][x] == BlocoSobrePlaca {
+		jogo.Mapa[y][x] = PlacaPressao
+	} else {
+		jogo.Mapa[y][x] = Vazio
+	}
+	if destino == PlacaPressao {
+		jogo.Mapa[ny][nx] = BlocoSobrePlaca
+	} else {
+		jogo.Mapa[ny][nx] = Bloco
+	}
+
+	jogoEmitirEvento(jogo, GameEvent{
+		Type: EventBlocoEmpurrado,
+		Data: BlocoEmpurradoData{De: Position{X: x, Y: y}, Para: Position{X: nx, Y: ny}},
+	})
+	return true
+}
+
+// Informa se o tile é uma placa, com ou sem bloco
+func jogoEhPlaca(elem Elemento) bool {
+	return elem == PlacaPressao || elem == BlocoSobrePlaca
+}
+
+// Recalcula os portões ligados às placas com o identificador: eles ficam
+// abertos enquanto todas essas placas tiverem um bloco em cima
+func jogoAtualizarPlacas(jogo *Jogo, id string) {
+	placas, pressionadas := 0, 0
+	for pos, it := range jogo.Interativos {
+		elem, _ := jogoElementoEm(jogo, pos.X, pos.Y)
+		if it.ID != id || !jogoEhPlaca(elem) {
+			continue
+		}
+		placas++
+		if elem == BlocoSobrePlaca {
+			pressionadas++
+		}
+	}
+	if placas == 0 {
+		return
+	}
+	abrir := pressionadas == placas
+
+	alterados, bloqueados := 0, 0
+	for pos, it := range jogo.Interativos {
+		if it.ID != id {
+			continue
+		}
+		elem, _ := jogoElementoEm(jogo, pos.X, pos.Y)
+		switch {
+		case abrir && elem == PortaoFechado:
+			jogoDefinirElemento(jogo, pos.X, pos.Y, PortaoAberto)
+			alterados++
+		case !abrir && elem == PortaoAberto:
+			if jogoPosicaoOcupada(jogo, pos.X, pos.Y) {
+				bloqueados++
+				continue
+			}
+			jogoDefinirElemento(jogo, pos.X, pos.Y, PortaoFechado)
+			alterados++
+		}
+	}
+	switch {
+	case bloqueados > 0:
+		jogoMensagem(jogo, SeveridadeAviso, "placa.bloqueada", id, bloqueados)
+	case alterados > 0 && abrir:
+		jogoMensagem(jogo, SeveridadeInfo, "placa.abriu", id)
+	case alterados > 0:
+		jogoMensagem(jogo, SeveridadeInfo, "placa.fechou", id)
+	}
+}
+
+// Identificador da placa no tile, ou "" se não houver placa
+func jogoIdentificadorPlaca(jogo *Jogo, p Position) string {
+	if elem, ok := jogoElementoEm(jogo, p.X, p.Y); ok && jogoEhPlaca(elem) {
+		return jogoIdentificador(jogo, p.X, p.Y)
+	}
+	return ""
+}
+
+// Trata o empurrão: as placas de onde o bloco saiu e para onde foi são reavaliadas
+func jogoTratarBlocoEmpurrado(jogo *Jogo, data BlocoEmpurradoData) {
+	for _, p := range []Position{data.De, data.Para} {
+		if id := jogoIdentificadorPlaca(jogo, p); id != "" {
+			jogoAtualizarPlacas(jogo, id)
+		}
+	}
+}
+
+// Devolve os blocos às posições do início do nível e recalcula os portões.
+// Não faz nada se o personagem ou o monstro estiverem em uma dessas posições.
+func jogoReiniciarQuebraCabeca(jogo *Jogo) {
+	if len(jogo.BlocosIniciais) == 0 {
+		jogoMensagem(jogo, SeveridadeInfo, "quebra_cabeca.sem_blocos")
+		return
+	}
+	for _, p := range jogo.BlocosIniciais {
+		if jogoPosicaoOcupada(jogo, p.X, p.Y) {
+			jogoMensagem(jogo, SeveridadeAviso, "quebra_cabeca.bloqueado")
+			return
+		}
+	}
+
+	for y, linha := range jogo.Mapa {
+		for x, elem := range linha {
+			switch elem {
+			case Bloco:
+				jogo.Mapa[y][x] = Vazio
+			case BlocoSobrePlaca:
+				jogo.Mapa[y][x] = PlacaPressao
+			}
+		}
+	}
+	for _, p := range jogo.BlocosIniciais {
+		if jogo.Mapa[p.Y][p.X] == PlacaPressao {
+			jogo.Mapa[p.Y][p.X] = BlocoSobrePlaca
+		} else {
+			jogo.Mapa[p.Y][p.X] = Bloco
+		}
+	}
+
+	// Cada identificador de placa é recalculado uma vez
+	ids := make(map[string]bool)
+	for pos := range jogo.Interativos {
+		if id := jogoIdentificadorPlaca(jogo, pos); id != "" && !ids[id] {
+			ids[id] = true
+			jogoAtualizarPlacas(jogo, id)
+		}
+	}
+	jogoMensagem(jogo, SeveridadeInfo, "quebra_cabeca.reiniciado")
+}
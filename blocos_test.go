@@ -0,0 +1,126 @@
+package main
+
+import "testing"
+
+// Duas placas ligadas ao portão; cada bloco vai para uma delas
+const blocosMapaDuasPlacas = `▤▤▤▤▤▤▤
+▤☺■◇▦ ▤
+▤ ■◇▤▤▤
+▤▤▤▤▤▤▤
+@ 3 1 a
+@ 3 2 a
+@ 4 1 a
+`
+
+// Placa com o portão aberto abaixo dela, onde o monstro pode ficar
+const blocosMapaPortaoAberto = `▤▤▤▤▤▤
+▤☺■◇ ▤
+▤☠▤▫▤▤
+▤▤▤▤▤▤
+@ 3 1 a
+@ 3 2 a
+`
+
+func TestBlocosEmpurrar(t *testing.T) {
+	casos := []struct {
+		nome     string
+		mapa     string
+		antes    func(jogo *Jogo) // ajuste antes das ações (opcional)
+		acoes    string
+		tiles    map[Position]Elemento
+		pos      Position
+		mensagem []interface{} // identificador e parâmetros da última mensagem; nil se não houver
+	}{
+		{
+			"bloco vai para o vazio", "▤▤▤▤▤\n▤☺■ ▤\n▤▤▤▤▤\n", nil, "d",
+			map[Position]Elemento{{X: 2, Y: 1}: Vazio, {X: 3, Y: 1}: Bloco}, Position{X: 2, Y: 1}, nil,
+		},
+		{
+			"bloco contra a parede", "▤▤▤▤\n▤☺■▤\n▤▤▤▤\n", nil, "d",
+			map[Position]Elemento{{X: 2, Y: 1}: Bloco}, Position{X: 1, Y: 1}, []interface{}{"bloco.preso"},
+		},
+		{
+			"bloco contra outro bloco", "▤▤▤▤▤▤\n▤☺■■ ▤\n▤▤▤▤▤▤\n", nil, "d",
+			map[Position]Elemento{{X: 2, Y: 1}: Bloco, {X: 3, Y: 1}: Bloco, {X: 4, Y: 1}: Vazio},
+			Position{X: 1, Y: 1}, []interface{}{"bloco.preso"},
+		},
+		{
+			"bloco contra o monstro", "▤▤▤▤▤▤\n▤☺■☠ ▤\n▤▤▤▤▤▤\n", nil, "d",
+			map[Position]Elemento{{X: 2, Y: 1}: Bloco, {X: 3, Y: 1}: Vazio},
+			Position{X: 1, Y: 1}, []interface{}{"bloco.preso"},
+		},
+		{
+			"bloco contra um item", "▤▤▤▤▤\n▤☺■⚷▤\n▤▤▤▤▤\n", nil, "d",
+			map[Position]Elemento{{X: 2, Y: 1}: Bloco, {X: 3, Y: 1}: Chave},
+			Position{X: 1, Y: 1}, []interface{}{"bloco.preso"},
+		},
+		{
+			"empurrar não gasta o pulo duplo", "▤▤▤▤▤▤\n▤☺■  ▤\n▤▤▤▤▤▤\n",
+			func(jogo *Jogo) { jogo.DoubleJumps = 2 }, "d",
+			map[Position]Elemento{{X: 3, Y: 1}: Bloco}, Position{X: 2, Y: 1}, nil,
+		},
+		{
+			"uma de duas placas não abre o portão", blocosMapaDuasPlacas, nil, "d",
+			map[Position]Elemento{{X: 3, Y: 1}: BlocoSobrePlaca, {X: 3, Y: 2}: PlacaPressao, {X: 4, Y: 1}: PortaoFechado},
+			Position{X: 2, Y: 1}, nil,
+		},
+		{
+			"as duas placas abrem o portão", blocosMapaDuasPlacas, nil, "dasd",
+			map[Position]Elemento{{X: 3, Y: 1}: BlocoSobrePlaca, {X: 3, Y: 2}: BlocoSobrePlaca, {X: 4, Y: 1}: PortaoAberto},
+			Position{X: 2, Y: 2}, []interface{}{"placa.abriu", "a"},
+		},
+		{
+			"bloco sai da placa e o portão fecha", "▤▤▤▤▤▤\n▤☺◆ ▫▤\n▤▤▤▤▤▤\n@ 2 1 a\n@ 4 1 a\n", nil, "d",
+			map[Position]Elemento{{X: 2, Y: 1}: PlacaPressao, {X: 3, Y: 1}: Bloco, {X: 4, Y: 1}: PortaoFechado},
+			Position{X: 2, Y: 1}, []interface{}{"placa.fechou", "a"},
+		},
+		{
+			"portão com o monstro não fecha", blocosMapaPortaoAberto,
+			func(jogo *Jogo) { jogo.Monstro.current_position = Position{X: 3, Y: 2} }, "dd",
+			map[Position]Elemento{{X: 3, Y: 1}: PlacaPressao, {X: 4, Y: 1}: Bloco, {X: 3, Y: 2}: PortaoAberto},
+			Position{X: 3, Y: 1}, []interface{}{"placa.bloqueada", "a", 1},
+		},
+		{
+			"reinício devolve os blocos e fecha o portão", blocosMapaDuasPlacas, nil, "dasdawr",
+			map[Position]Elemento{
+				{X: 2, Y: 1}: Bloco, {X: 2, Y: 2}: Bloco,
+				{X: 3, Y: 1}: PlacaPressao, {X: 3, Y: 2}: PlacaPressao, {X: 4, Y: 1}: PortaoFechado,
+			},
+			Position{X: 1, Y: 1}, []interface{}{"quebra_cabeca.reiniciado"},
+		},
+		{
+			"reinício com o personagem no lugar de um bloco", blocosMapaDuasPlacas, nil, "dr",
+			map[Position]Elemento{{X: 3, Y: 1}: BlocoSobrePlaca},
+			Position{X: 2, Y: 1}, []interface{}{"quebra_cabeca.bloqueado"},
+		},
+		{
+			"reinício sem blocos", "▤▤▤\n▤☺▤\n▤▤▤\n", nil, "r",
+			nil, Position{X: 1, Y: 1}, []interface{}{"quebra_cabeca.sem_blocos"},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			jogo := interacaoJogoTeste(t, c.mapa)
+			if c.antes != nil {
+				c.antes(jogo)
+			}
+			interacaoJogar(jogo, c.acoes)
+
+			for p, esperado := range c.tiles {
+				if elem, _ := jogoElementoEm(jogo, p.X, p.Y); elem != esperado {
+					t.Errorf("tile (%d, %d) = %q, esperado %q", p.X, p.Y, elem.simbolo, esperado.simbolo)
+				}
+			}
+			if pos := (Position{X: jogo.PosX, Y: jogo.PosY}); pos != c.pos {
+				t.Errorf("personagem em %v, esperado em %v", pos, c.pos)
+			}
+			esperada := ""
+			if c.mensagem != nil {
+				esperada = traduzir(c.mensagem[0].(string), c.mensagem[1:]...)
+			}
+			if got := interacaoUltimaMensagem(jogo); got != esperada {
+				t.Errorf("última mensagem %q, esperada %q", got, esperada)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// camera.go - Câmera que acompanha o personagem em mapas maiores que o terminal
+package main
+
+// Tamanho padrão da zona morta (distância do centro da tela que o personagem
+// pode andar antes da câmera se mover)
+const (
+	CameraZonaMortaPadraoX = 8
+	CameraZonaMortaPadraoY = 4
+)
+
+// Camera representa a janela visível do mapa
+type Camera struct {
+	X, Y                   int // canto superior esquerdo da janela, em coordenadas do mapa
+	Largura, Altura        int // tamanho da janela em células
+	ZonaMortaX, ZonaMortaY int // meia largura/altura da zona morta ao redor do centro
+}
+
+func cameraNova(zonaMortaX, zonaMortaY int) Camera {
+	return Camera{
+		ZonaMortaX: zonaMortaX,
+		ZonaMortaY: zonaMortaY,
+	}
+}
+
+// Retorna a largura (maior linha) e a altura do mapa
+func jogoTamanhoMapa(jogo *Jogo) (int, int) {
+	largura := 0
+	for _, linha := range jogo.Mapa {
+		if len(linha) > largura {
+			largura = len(linha)
+		}
+	}
+	return largura, len(jogo.Mapa)
+}
+
+// Ajusta a câmera para o tamanho da janela e move-a quando o alvo sai da zona morta
+func cameraSeguir(cam *Camera, alvoX, alvoY, largura, altura, mapaLargura, mapaAltura int) {
+	cam.Largura, cam.Altura = largura, altura
+	cam.X = cameraAjustarEixo(cam.X, alvoX, largura, cam.ZonaMortaX, mapaLargura)
+	cam.Y = cameraAjustarEixo(cam.Y, alvoY, altura, cam.ZonaMortaY, mapaAltura)
+}
+
+// Calcula o deslocamento da câmera em um único eixo
+func cameraAjustarEixo(inicio, alvo, tamanho, zonaMorta, limite int) int {
+	if tamanho <= 0 {
+		return 0
+	}
+
+	// A zona morta nunca pode ser maior que metade da janela
+	if zonaMorta > (tamanho-1)/2 {
+		zonaMorta = (tamanho - 1) / 2
+	}
+	if zonaMorta < 0 {
+		zonaMorta = 0
+	}
+
+	centro := inicio + tamanho/2
+	if alvo < centro-zonaMorta {
+		inicio = alvo + zonaMorta - tamanho/2
+	} else if alvo > centro+zonaMorta {
+		inicio = alvo - zonaMorta - tamanho/2
+	}
+
+	// Mantém a janela dentro do mapa; mapas menores que a tela ficam ancorados em 0
+	if inicio > limite-tamanho {
+		inicio = limite - tamanho
+	}
+	if inicio < 0 {
+		inicio = 0
+	}
+	return inicio
+}
+
+// Converte uma posição do mapa para coordenadas de tela
+func cameraParaTela(cam *Camera, x, y int) (int, int, bool) {
+	tx, ty := x-cam.X, y-cam.Y
+	if tx < 0 || ty < 0 || tx >= cam.Largura || ty >= cam.Altura {
+		return 0, 0, false
+	}
+	return tx, ty, true
+}
@@ -0,0 +1,132 @@
+// caminho.go - Busca de caminhos no mapa para o movimento dos monstros
+package main
+
+// Alcance, em passos, da rota de fuga calculada para um monstro assustado
+const MaxPassosFuga = 12
+
+// Limite de passos das rotas do monstro até o destino e do caminho planejado
+// desenhado para ele
+const MaxPassosCaminho = 80
+
+// Posições vizinhas para onde se pode andar a partir de p. Quem entra em um
+// portal que leva monstros sai no par dele, então os vizinhos passam a ser os
+// do par; atravessa é falso na origem da busca, onde o monstro já está, e
+// enquanto a recarga de portais dele não acabou.
+func caminhoVizinhos(jogo *Jogo, p Position, atravessa bool) []Position {
+	if destino, ok := jogoPortalDestino(jogo, p, true); ok && atravessa {
+		p = destino
+	}
+	var vizinhos []Position
+	for _, d := range []Position{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}} {
+		v := Position{X: p.X + d.X, Y: p.Y + d.Y}
+		if jogoPodeMoverPara(jogo, v.X, v.Y) {
+			vizinhos = append(vizinhos, v)
+		}
+	}
+	return vizinhos
+}
+
+// Busca em largura a partir da origem, até limite passos (negativo não limita).
+// Retorna a distância de cada posição alcançada e de onde se chegou a ela.
+// Os portais só levam ao par a partir de recarga passos, quando a recarga de
+// quem anda terá acabado. As posições bloqueadas não são atravessadas, como a
+// do personagem para um monstro em fuga.
+func caminhoBusca(jogo *Jogo, origem Position, limite, recarga int, bloqueadas ...Position) (map[Position]int, map[Position]Position) {
+	distancia := map[Position]int{origem: 0}
+	anterior := make(map[Position]Position)
+	for _, b := range bloqueadas {
+		if b != origem {
+			distancia[b] = -1
+		}
+	}
+	fila := []Position{origem}
+	for len(fila) > 0 {
+		p := fila[0]
+		fila = fila[1:]
+		if limite >= 0 && distancia[p] >= limite {
+			continue
+		}
+		for _, v := range caminhoVizinhos(jogo, p, p != origem && distancia[p] >= recarga) {
+			if _, visto := distancia[v]; visto {
+				continue
+			}
+			distancia[v] = distancia[p] + 1
+			anterior[v] = p
+			fila = append(fila, v)
+		}
+	}
+	for _, b := range bloqueadas {
+		if b != origem {
+			delete(distancia, b)
+		}
+	}
+	return distancia, anterior
+}
+
+// Refaz o caminho até o destino, sem a origem
+func caminhoReconstruir(anterior map[Position]Position, origem, destino Position) []Position {
+	var caminho []Position
+	for p := destino; p != origem; p = anterior[p] {
+		caminho = append([]Position{p}, caminho...)
+	}
+	return caminho
+}
+
+// Rota de fuga: entre as posições alcançáveis em até MaxPassosFuga passos sem
+// cruzar a ameaça, escolhe a que fica mais longe dela pelo mapa
+func caminhoFuga(jogo *Jogo, origem, ameaca Position, recarga int) []Position {
+	distanciaAmeaca, _ := caminhoBusca(jogo, ameaca, -1, 0)
+	alcance, anterior := caminhoBusca(jogo, origem, MaxPassosFuga, recarga, ameaca)
+
+	// Tiles que a ameaça não alcança são os mais seguros
+	seguranca := func(p Position) int {
+		if d, ok := distanciaAmeaca[p]; ok {
+			return d
+		}
+		return len(distanciaAmeaca) + 1
+	}
+	melhor := origem
+	for p, passos := range alcance {
+		d, dm := seguranca(p), seguranca(melhor)
+		// No empate fica o mais perto da origem e, por fim, a menor posição,
+		// para a escolha não depender da ordem do map
+		if d > dm || d == dm && (passos < alcance[melhor] || passos == alcance[melhor] && posicaoMenor(p, melhor)) {
+			melhor = p
+		}
+	}
+	if melhor == origem {
+		return nil
+	}
+	return caminhoReconstruir(anterior, origem, melhor)
+}
+
+// Rota até o destino pelo mapa, atravessando portais, em até MaxPassosCaminho
+// passos. Se o destino não puder ser alcançado (uma parede, o tile do
+// personagem), a rota leva à posição alcançável mais perto dele.
+func caminhoAte(jogo *Jogo, origem, destino Position, recarga int) []Position {
+	alcance, anterior := caminhoBusca(jogo, origem, MaxPassosCaminho, recarga)
+	distanciaReta := func(p Position) int { return abs(p.X-destino.X) + abs(p.Y-destino.Y) }
+	melhor := origem
+	for p, passos := range alcance {
+		d, dm := distanciaReta(p), distanciaReta(melhor)
+		if d < dm || d == dm && (passos < alcance[melhor] || passos == alcance[melhor] && posicaoMenor(p, melhor)) {
+			melhor = p
+		}
+	}
+	if melhor == origem {
+		return nil
+	}
+	return caminhoReconstruir(anterior, origem, melhor)
+}
+
+// Calcula a rota do monstro até o destino pedido e a envia a ele; com o canal
+// cheio, o monstro continua pelo passo direto até pedir a rota de outro destino
+func jogoEnviarRotaMonstro(jogo *Jogo, destino Position) {
+	m := jogo.Monstro
+	rota := caminhoAte(jogo, m.current_position, destino, jogo.RecargaPortais[m.id])
+	jogoAlertarMonstro(jogo, PlayerAlert{Type: "route", Data: MonsterRouteData{MonsterID: m.id, Para: destino, Caminho: rota}})
+}
+
+func posicaoMenor(a, b Position) bool {
+	return a.Y < b.Y || a.Y == b.Y && a.X < b.X
+}
@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+// Duas salas separadas por uma parede em x=6, ligadas pelos portais em
+// (3,2) e (8,2); o monstro está em (2,2) e quer chegar a (9,2)
+func caminhoJogoComPortais(t *testing.T) *Jogo {
+	t.Helper()
+	jogo := telaJogoTeste(12, 5, 10, 3)
+	for y := range jogo.Mapa {
+		jogo.Mapa[y][6] = Parede
+	}
+	jogo.Mapa[2][3] = Portal
+	jogo.Mapa[2][8] = Portal
+	if err := jogoLigarPortais(jogo); err != nil {
+		t.Fatal(err)
+	}
+	return jogo
+}
+
+func TestCaminhoAteAtravessaPortal(t *testing.T) {
+	jogo := caminhoJogoComPortais(t)
+	rota := caminhoAte(jogo, Position{X: 2, Y: 2}, Position{X: 9, Y: 2}, 0)
+	esperada := []Position{{X: 3, Y: 2}, {X: 9, Y: 2}}
+	if len(rota) != len(esperada) || rota[0] != esperada[0] || rota[1] != esperada[1] {
+		t.Fatalf("rota = %v, esperada %v", rota, esperada)
+	}
+}
+
+// Com a recarga ainda correndo o monstro não é levado ao par, então a rota
+// não pode saltar de um portal para o outro lado do mapa
+func TestCaminhoAteRespeitaRecarga(t *testing.T) {
+	jogo := caminhoJogoComPortais(t)
+	origem := Position{X: 2, Y: 2}
+	rota := caminhoAte(jogo, origem, Position{X: 9, Y: 2}, RecargaPortal)
+	if len(rota) == 0 {
+		t.Fatal("rota vazia; esperado chegar o mais perto possível do destino")
+	}
+	anterior := origem
+	for _, p := range rota {
+		if abs(p.X-anterior.X)+abs(p.Y-anterior.Y) != 1 {
+			t.Fatalf("rota %v salta de %v para %v", rota, anterior, p)
+		}
+		if p.X > 6 {
+			t.Fatalf("rota %v passa para a outra sala", rota)
+		}
+		anterior = p
+	}
+}
+
+func TestCaminhoFugaRespeitaRecarga(t *testing.T) {
+	jogo := caminhoJogoComPortais(t)
+	origem := Position{X: 2, Y: 2}
+	rota := caminhoFuga(jogo, origem, Position{X: 1, Y: 2}, RecargaPortal)
+	anterior := origem
+	for _, p := range rota {
+		if abs(p.X-anterior.X)+abs(p.Y-anterior.Y) != 1 {
+			t.Fatalf("rota de fuga %v salta de %v para %v", rota, anterior, p)
+		}
+		anterior = p
+	}
+}
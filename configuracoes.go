@@ -0,0 +1,210 @@
+// configuracoes.go - Opções do jogador gravadas no diretório de configuração
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Configuracoes são as opções alteráveis pela tela de opções
+type Configuracoes struct {
+	ZonaMortaX int `json:"zona_morta_x"`
+	ZonaMortaY int `json:"zona_morta_y"`
+
+	// Exibe também as mensagens de detalhe (mudanças de estado das estrelas etc.)
+	MensagensDetalhadas bool `json:"mensagens_detalhadas"`
+
+	// Teclas de cada ação; ações ausentes no arquivo mantêm as teclas padrão
+	Teclas MapaTeclas `json:"teclas"`
+
+	// Idioma dos textos ("pt-BR", "en"); vazio segue o idioma do sistema
+	Idioma string `json:"idioma"`
+
+	// Tema visual (símbolos e cores); vazio usa o tema Unicode
+	Tema string `json:"tema"`
+
+	// Neblina de guerra: só o campo de visão do personagem é exibido por completo
+	Neblina bool `json:"neblina"`
+
+	// Formato e sobreposições da captura de tela, os mesmos de "jogo export"
+	CapturaFormato  string `json:"captura_formato"`
+	CapturaCaminhos bool   `json:"captura_caminhos"`
+	CapturaAlcance  bool   `json:"captura_alcance"`
+}
+
+func configuracoesPadrao() *Configuracoes {
+	return &Configuracoes{
+		ZonaMortaX: CameraZonaMortaPadraoX,
+		ZonaMortaY: CameraZonaMortaPadraoY,
+		Teclas:     teclasPadrao(),
+
+		CapturaFormato: FormatoSVG,
+	}
+}
+
+// Diretório onde o jogo guarda configurações e recordes
+func diretorioConfiguracao() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fppd-jogo"), nil
+}
+
+func configuracoesCaminho() (string, error) {
+	dir, err := diretorioConfiguracao()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Lê as configurações; opções ausentes no arquivo mantêm o valor padrão.
+// Um erro junto com configurações não nulas é apenas um aviso.
+func configuracoesCarregar() (*Configuracoes, error) {
+	cfg := configuracoesPadrao()
+	caminho, err := configuracoesCaminho()
+	if err != nil {
+		return cfg, err
+	}
+	dados, err := os.ReadFile(caminho)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(dados, cfg); err != nil {
+		return configuracoesPadrao(), err
+	}
+	// Conflitos não impedem o jogo: a tecla fica com a primeira ação
+	return cfg, teclasValidar(cfg.Teclas)
+}
+
+func configuracoesSalvar(cfg *Configuracoes) error {
+	caminho, err := configuracoesCaminho()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(caminho), 0755); err != nil {
+		return err
+	}
+	dados, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(caminho, dados, 0644)
+}
+
+// Limita um valor ao intervalo [minimo, maximo]
+func limitar(valor, minimo, maximo int) int {
+	if valor < minimo {
+		return minimo
+	}
+	if valor > maximo {
+		return maximo
+	}
+	return valor
+}
+
+// Menu de opções; as alterações são gravadas ao sair dele
+func menuOpcoes(cfg *Configuracoes) *Menu {
+	return &Menu{
+		Titulo: traduzir("opcoes.titulo"),
+		Opcoes: []OpcaoMenu{
+			{
+				Rotulo:  traduzir("opcoes.zona_x"),
+				Valor:   func() string { return fmt.Sprint(cfg.ZonaMortaX) },
+				Ajustar: func(d int) { cfg.ZonaMortaX = limitar(cfg.ZonaMortaX+d, 0, 40) },
+			},
+			{
+				Rotulo:  traduzir("opcoes.zona_y"),
+				Valor:   func() string { return fmt.Sprint(cfg.ZonaMortaY) },
+				Ajustar: func(d int) { cfg.ZonaMortaY = limitar(cfg.ZonaMortaY+d, 0, 20) },
+			},
+			{
+				Rotulo:  traduzir("opcoes.detalhes"),
+				Valor:   func() string { return simNao(cfg.MensagensDetalhadas) },
+				Ajustar: func(int) { cfg.MensagensDetalhadas = !cfg.MensagensDetalhadas },
+			},
+			{
+				Rotulo:  traduzir("opcoes.neblina"),
+				Valor:   func() string { return simNao(cfg.Neblina) },
+				Ajustar: func(int) { cfg.Neblina = !cfg.Neblina },
+			},
+			{
+				Rotulo:   traduzir("opcoes.teclas"),
+				Executar: func() { interfaceRedefinirTeclas(cfg.Teclas) },
+			},
+			{
+				Rotulo: traduzir("opcoes.idioma"),
+				Valor: func() string {
+					if cfg.Idioma == "" {
+						return traduzir("opcoes.idioma_sistema")
+					}
+					return cfg.Idioma
+				},
+				Ajustar: func(d int) {
+					cfg.Idioma = idiomaProximo(cfg.Idioma, d)
+					idiomaAtual = idiomaEscolher(cfg.Idioma)
+				},
+			},
+			{
+				Rotulo: traduzir("opcoes.tema"),
+				Valor:  func() string { return traduzir("tema." + temaAtual.Nome) },
+				Ajustar: func(d int) {
+					cfg.Tema = alternar(temasDisponiveis(), temaAtual.Nome, d)
+					temaAtual, _ = temaEscolher(cfg.Tema)
+				},
+			},
+			{
+				Rotulo:  traduzir("opcoes.captura"),
+				Valor:   func() string { return cfg.CapturaFormato },
+				Ajustar: func(d int) { cfg.CapturaFormato = alternar([]string{FormatoSVG, FormatoHTML}, cfg.CapturaFormato, d) },
+			},
+			{
+				Rotulo:  traduzir("opcoes.captura_rota"),
+				Valor:   func() string { return simNao(cfg.CapturaCaminhos) },
+				Ajustar: func(int) { cfg.CapturaCaminhos = !cfg.CapturaCaminhos },
+			},
+			{
+				Rotulo:  traduzir("opcoes.captura_itens"),
+				Valor:   func() string { return simNao(cfg.CapturaAlcance) },
+				Ajustar: func(int) { cfg.CapturaAlcance = !cfg.CapturaAlcance },
+			},
+		},
+		AoFechar: func() error {
+			if err := configuracoesSalvar(cfg); err != nil {
+				return textosErro("opcoes.erro_salvar", err)
+			}
+			return nil
+		},
+	}
+}
+
+func simNao(v bool) string {
+	if v {
+		return traduzir("opcoes.sim")
+	}
+	return traduzir("opcoes.nao")
+}
+
+// Avança d posições na lista de idiomas, que começa pelo idioma do sistema ("")
+func idiomaProximo(atual string, d int) string {
+	return alternar(append([]string{""}, idiomasDisponiveis()...), atual, d)
+}
+
+// Avança d posições em uma lista circular de valores; um valor fora da
+// lista conta como o primeiro
+func alternar(valores []string, atual string, d int) string {
+	i := 0
+	for j, v := range valores {
+		if v == atual {
+			i = j
+		}
+	}
+	n := len(valores)
+	return valores[((i+d)%n+n)%n]
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Usa um diretório de configuração temporário e retorna o caminho do arquivo
+func configuracoesDiretorioTeste(t *testing.T) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	caminho, err := configuracoesCaminho()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return caminho
+}
+
+// Opções ausentes no arquivo, inclusive ações sem teclas gravadas, ficam com o padrão
+func TestConfiguracoesParciais(t *testing.T) {
+	caminho := configuracoesDiretorioTeste(t)
+	if err := os.MkdirAll(filepath.Dir(caminho), 0755); err != nil {
+		t.Fatal(err)
+	}
+	parcial := `{"zona_morta_x": 3, "neblina": true, "teclas": {"cima": ["i"]}}`
+	if err := os.WriteFile(caminho, []byte(parcial), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := configuracoesCarregar()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	esperado := configuracoesPadrao()
+	esperado.ZonaMortaX = 3
+	esperado.Neblina = true
+	esperado.Teclas[AcaoCima] = []string{"i"}
+	if !reflect.DeepEqual(cfg, esperado) {
+		t.Errorf("configurações = %+v, esperadas %+v", cfg, esperado)
+	}
+}
+
+func TestConfiguracoesCarregarErros(t *testing.T) {
+	casos := []struct {
+		nome      string
+		conteudo  string
+		padrao    bool // o arquivo inteiro é descartado
+		conflitos bool
+	}{
+		{"json inválido", `{"zona_morta_x": `, true, false},
+		{"tecla em duas ações", `{"zona_morta_x": 5, "teclas": {"salvar": ["w"]}}`, false, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			caminho := configuracoesDiretorioTeste(t)
+			if err := os.MkdirAll(filepath.Dir(caminho), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(caminho, []byte(c.conteudo), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := configuracoesCarregar()
+			if err == nil {
+				t.Fatal("arquivo carregado sem erro")
+			}
+			if c.padrao != reflect.DeepEqual(cfg, configuracoesPadrao()) {
+				t.Errorf("configurações = %+v", cfg)
+			}
+			if _, ok := err.(ErroTexto); ok != c.conflitos {
+				t.Errorf("erro %v", err)
+			}
+		})
+	}
+}
+
+// Sem conseguir gravar, o menu de opções avisa no menu de onde foi aberto
+func TestConfiguracoesErroAoSalvar(t *testing.T) {
+	// O diretório de configuração é um arquivo: não dá para criar o diretório do jogo
+	arquivo := filepath.Join(t.TempDir(), "arquivo")
+	if err := os.WriteFile(arquivo, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", arquivo)
+
+	principal := &Menu{Titulo: "principal"}
+	pilha := menuFechar([]*Menu{principal, menuOpcoes(configuracoesPadrao())})
+	if len(pilha) != 1 || pilha[0] != principal {
+		t.Fatalf("pilha depois de fechar: %v", pilha)
+	}
+	if prefixo := strings.SplitN(traduzir("opcoes.erro_salvar"), "%", 2)[0]; principal.Status == "" || !strings.HasPrefix(principal.Status, prefixo) {
+		t.Errorf("status = %q, esperado o erro ao salvar", principal.Status)
+	}
+
+	// Gravando, nada é avisado e o arquivo fica com as opções
+	configuracoesDiretorioTeste(t)
+	principal.Status = ""
+	cfg := configuracoesPadrao()
+	cfg.Neblina = true
+	menuFechar([]*Menu{principal, menuOpcoes(cfg)})
+	if principal.Status != "" {
+		t.Errorf("status = %q depois de salvar", principal.Status)
+	}
+	if lido, err := configuracoesCarregar(); err != nil || !lido.Neblina {
+		t.Errorf("opções gravadas = %+v, %v", lido, err)
+	}
+}
@@ -0,0 +1,451 @@
+// console.go - Console de desenvolvimento com comandos para as estrelas, o monstro e o personagem
+package main
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Limites do histórico de comandos e das linhas de saída guardadas
+const (
+	MaxHistoricoConsole = 100
+	MaxSaidaConsole     = 200
+	LinhasConsole       = 8 // linhas de saída visíveis acima do prompt
+)
+
+// Console guarda o histórico e a saída entre uma abertura e outra
+type Console struct {
+	Historico []string
+	Saida     []Indicador
+}
+
+func consoleNovo() *Console {
+	return &Console{}
+}
+
+// Comando do console: executa com os argumentos e completa o argumento de
+// índice n (0 é o primeiro argumento depois do nome)
+type ComandoConsole struct {
+	Nome      string
+	Executar  func(jogo *Jogo, args []string) (string, error)
+	Completar func(jogo *Jogo, n int, args []string) []string
+}
+
+func consoleComandos() []ComandoConsole {
+	return []ComandoConsole{
+		{Nome: "give", Executar: consoleDar, Completar: consoleCompletarDar},
+		{Nome: "help", Executar: consoleAjuda},
+		{Nome: "monster", Executar: consoleMonstro, Completar: consoleCompletarMonstro},
+		{Nome: "spawn", Executar: consoleCriar, Completar: consoleCompletarCriar},
+		{Nome: "star", Executar: consoleEstrela, Completar: consoleCompletarEstrela},
+		{Nome: "teleport", Executar: consoleTeleportar},
+	}
+}
+
+// Nomes dos estados aceitos pelos comandos star e monster
+var (
+	consoleEstadosEstrela = map[string]StarState{
+		"visible":   StarVisible,
+		"invisible": StarInvisible,
+		"pulsing":   StarPulsing,
+		"charging":  StarCharging,
+	}
+	consoleEstadosMonstro = map[string]MonsterState{
+		"hunting":    Hunting,
+		"patrolling": Patrolling,
+	}
+	consoleItens = []string{"invis", "jump", "life", "power", "score"}
+)
+
+// Executa uma linha de comando e retorna o texto de resposta
+func consoleExecutar(jogo *Jogo, linha string) (string, error) {
+	palavras := strings.Fields(linha)
+	if len(palavras) == 0 {
+		return "", nil
+	}
+	if jogo.Gravacao != nil {
+		// Os comandos não entram na gravação e tornariam o replay diferente da partida
+		return "", errors.New(traduzir("console.gravando"))
+	}
+	for _, c := range consoleComandos() {
+		if c.Nome == palavras[0] {
+			return c.Executar(jogo, palavras[1:])
+		}
+	}
+	return "", errors.New(traduzir("console.desconhecido", palavras[0]))
+}
+
+// Erro com o uso correto do comando
+func consoleUso(nome string) error {
+	return errors.New(traduzir("console.uso", traduzir("console.uso."+nome)))
+}
+
+func consoleAjuda(jogo *Jogo, args []string) (string, error) {
+	var usos []string
+	for _, c := range consoleComandos() {
+		usos = append(usos, traduzir("console.uso."+c.Nome))
+	}
+	return strings.Join(usos, "  |  "), nil
+}
+
+// star <id> pulse | charge | state <estado>
+func consoleEstrela(jogo *Jogo, args []string) (string, error) {
+	if len(args) < 2 {
+		return "", consoleUso("star")
+	}
+	if jogoEstrelaPorID(jogo, args[0]) == nil {
+		return "", errors.New(traduzir("console.estrela_desconhecida", args[0]))
+	}
+	comando := StarCommand{Type: args[1], Target: args[0]}
+	switch args[1] {
+	case "pulse", "charge":
+	case "state":
+		if len(args) < 3 {
+			return "", consoleUso("star")
+		}
+		estado, ok := consoleEstadosEstrela[args[2]]
+		if !ok {
+			return "", errors.New(traduzir("console.estado_invalido", args[2]))
+		}
+		comando = StarCommand{Type: "change_state", Target: args[0], Data: estado}
+	default:
+		return "", consoleUso("star")
+	}
+	if !jogoEnviarComandoEstrela(jogo, comando) {
+		return "", errors.New(traduzir("console.nao_entregue"))
+	}
+	return traduzir("console.estrela_ok", strings.Join(args[1:], " "), args[0]), nil
+}
+
+// monster <id> state <estado>
+func consoleMonstro(jogo *Jogo, args []string) (string, error) {
+	if len(args) < 3 || args[1] != "state" {
+		return "", consoleUso("monster")
+	}
+	if jogo.Monstro == nil || jogo.Monstro.id != args[0] {
+		return "", errors.New(traduzir("console.monstro_desconhecido", args[0]))
+	}
+	estado, ok := consoleEstadosMonstro[args[2]]
+	if !ok {
+		return "", errors.New(traduzir("console.estado_invalido", args[2]))
+	}
+	// Com o jogo congelado o monstro não lê os alertas, então o envio não pode
+	// esperar; entregue, o estado vale como uma mudança imposta pelo jogo
+	select {
+	case jogo.PlayerAlerts <- PlayerAlert{Type: "set_state", Data: estado, Epoca: jogo.EpocaMonstro + 1}:
+		jogo.EpocaMonstro++
+		jogo.EstadoMonstros[jogo.Monstro.id] = estado
+	default:
+		descartesRegistrar("alert:set_state")
+		return "", errors.New(traduzir("console.nao_entregue"))
+	}
+	return traduzir("console.monstro_ok", args[0], args[2]), nil
+}
+
+// teleport x y
+func consoleTeleportar(jogo *Jogo, args []string) (string, error) {
+	if len(args) != 2 {
+		return "", consoleUso("teleport")
+	}
+	x, errX := strconv.Atoi(args[0])
+	y, errY := strconv.Atoi(args[1])
+	if errX != nil || errY != nil {
+		return "", consoleUso("teleport")
+	}
+	if !jogoPodeMoverPara(jogo, x, y) {
+		return "", errors.New(traduzir("console.posicao_invalida", x, y))
+	}
+	jogoMoverElemento(jogo, jogo.PosX, jogo.PosY, x-jogo.PosX, y-jogo.PosY)
+	jogo.PosX, jogo.PosY = x, y
+	jogoEnviarEstadoJogador(jogo)
+	personagemVerificarSaida(jogo)
+	return traduzir("console.teleporte_ok", x, y), nil
+}
+
+// give <item> <quantidade>
+func consoleDar(jogo *Jogo, args []string) (string, error) {
+	if len(args) != 2 {
+		return "", consoleUso("give")
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n <= 0 {
+		return "", consoleUso("give")
+	}
+	switch args[0] {
+	case "invis":
+		jogo.InvisibleSteps += n
+	case "jump":
+		jogo.DoubleJumps += n
+	case "life":
+		jogo.Partida.Vidas += n
+	case "power":
+		jogoAtivarPoder(jogo, jogo.PowerSteps+n)
+	case "score":
+		jogoAdicionarPontos(jogo, n)
+	default:
+		return "", consoleUso("give")
+	}
+	return traduzir("console.dar_ok", n, args[0]), nil
+}
+
+// spawn <símbolo> x y
+func consoleCriar(jogo *Jogo, args []string) (string, error) {
+	if len(args) != 3 {
+		return "", consoleUso("spawn")
+	}
+	simbolo := []rune(args[0])[0]
+	elem := jogoElementoPorSimbolo(simbolo)
+	if elem == Vazio {
+		return "", errors.New(traduzir("console.simbolo_desconhecido", args[0]))
+	}
+	x, errX := strconv.Atoi(args[1])
+	y, errY := strconv.Atoi(args[2])
+	if errX != nil || errY != nil {
+		return "", consoleUso("spawn")
+	}
+	if !jogoTileLivre(jogo, x, y) {
+		return "", errors.New(traduzir("console.posicao_invalida", x, y))
+	}
+
+	pos := Position{X: x, Y: y}
+	switch elem.simbolo {
+	case StarElementVisible.simbolo:
+		star := jogoCriarEstrela(jogo, x, y)
+		return traduzir("console.estrela_criada", star.ID, x, y), nil
+	case InvisibilityItem.simbolo:
+		jogoCriarInvisibilidade(jogo, x, y)
+	case JatoApagado.simbolo, JatoAceso.simbolo:
+		jogoCriarJato(jogo, x, y)
+	default:
+		jogo.Mapa[y][x] = elem
+		if tileTipo(elem).Interativo {
+			jogo.Interativos[pos] = &Interativo{ID: IdentificadorPadrao}
+		}
+		if elem == Portal {
+			// O par se forma quando o segundo portal com o identificador for criado
+			jogoLigarPortais(jogo)
+		}
+	}
+	return traduzir("console.criado", args[0], x, y), nil
+}
+
+func consoleCompletarEstrela(jogo *Jogo, n int, args []string) []string {
+	switch {
+	case n == 0:
+		var ids []string
+		for _, s := range jogo.Stars {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	case n == 1:
+		return []string{"charge", "pulse", "state"}
+	case n == 2 && args[1] == "state":
+		return consoleChaves(consoleEstadosEstrela)
+	}
+	return nil
+}
+
+func consoleCompletarMonstro(jogo *Jogo, n int, args []string) []string {
+	switch {
+	case n == 0 && jogo.Monstro != nil:
+		return []string{jogo.Monstro.id}
+	case n == 1:
+		return []string{"state"}
+	case n == 2 && args[1] == "state":
+		var estados []string
+		for nome := range consoleEstadosMonstro {
+			estados = append(estados, nome)
+		}
+		sort.Strings(estados)
+		return estados
+	}
+	return nil
+}
+
+func consoleCompletarDar(jogo *Jogo, n int, args []string) []string {
+	if n == 0 {
+		return consoleItens
+	}
+	return nil
+}
+
+func consoleCompletarCriar(jogo *Jogo, n int, args []string) []string {
+	if n != 0 {
+		return nil
+	}
+	var simbolos []string
+	for _, e := range jogoElementosDoMapa() {
+		simbolos = append(simbolos, string(e.simbolo))
+	}
+	return simbolos
+}
+
+func consoleChaves(m map[string]StarState) []string {
+	var chaves []string
+	for k := range m {
+		chaves = append(chaves, k)
+	}
+	sort.Strings(chaves)
+	return chaves
+}
+
+// Completa a última palavra da linha. Retorna a linha completada e, se houver
+// mais de uma possibilidade, as opções para exibir.
+func consoleCompletar(jogo *Jogo, linha string) (string, []string) {
+	palavras := strings.Fields(linha)
+	if len(palavras) == 0 || strings.HasSuffix(linha, " ") {
+		palavras = append(palavras, "")
+	}
+	atual := palavras[len(palavras)-1]
+
+	var candidatos []string
+	if len(palavras) == 1 {
+		for _, c := range consoleComandos() {
+			candidatos = append(candidatos, c.Nome)
+		}
+	} else {
+		for _, c := range consoleComandos() {
+			if c.Nome == palavras[0] && c.Completar != nil {
+				args := palavras[1:]
+				candidatos = c.Completar(jogo, len(args)-1, args)
+			}
+		}
+	}
+
+	var opcoes []string
+	for _, c := range candidatos {
+		if strings.HasPrefix(c, atual) {
+			opcoes = append(opcoes, c)
+		}
+	}
+	if len(opcoes) == 0 {
+		return linha, nil
+	}
+
+	inicio := linha[:len(linha)-len(atual)]
+	if len(opcoes) == 1 {
+		return inicio + opcoes[0] + " ", nil
+	}
+	return inicio + prefixoComum(opcoes), opcoes
+}
+
+// Maior prefixo compartilhado por todas as palavras
+func prefixoComum(palavras []string) string {
+	prefixo := palavras[0]
+	for _, p := range palavras[1:] {
+		for !strings.HasPrefix(p, prefixo) {
+			prefixo = prefixo[:len(prefixo)-1]
+		}
+	}
+	return prefixo
+}
+
+func consoleEscrever(c *Console, texto string, cor Cor) {
+	c.Saida = append(c.Saida, Indicador{Texto: texto, Cor: cor})
+	if len(c.Saida) > MaxSaidaConsole {
+		c.Saida = c.Saida[len(c.Saida)-MaxSaidaConsole:]
+	}
+}
+
+// Executa a linha, guarda-a no histórico e escreve a resposta na saída
+func consoleEnviar(jogo *Jogo, linha string) {
+	c := jogo.Console
+	linha = strings.TrimSpace(linha)
+	if linha == "" {
+		return
+	}
+	if n := len(c.Historico); n == 0 || c.Historico[n-1] != linha {
+		c.Historico = append(c.Historico, linha)
+		if len(c.Historico) > MaxHistoricoConsole {
+			c.Historico = c.Historico[1:]
+		}
+	}
+	consoleEscrever(c, "> "+linha, CorTexto)
+	resposta, err := consoleExecutar(jogo, linha)
+	switch {
+	case err != nil:
+		consoleEscrever(c, err.Error(), CorVermelho)
+	case resposta != "":
+		consoleEscrever(c, resposta, CorVerde)
+	}
+}
+
+// Abre o console sobre o jogo congelado; ESC ou a tecla do console fecham
+func interfaceConsole(jogo *Jogo) {
+	if jogo.Console == nil {
+		jogo.Console = consoleNovo()
+	}
+	c := jogo.Console
+	var texto []rune
+	indiceHistorico := len(c.Historico)
+
+	for {
+		interfaceDesenharJogo(jogo)
+		interfaceDesenharConsole(c, string(texto))
+		interfaceAtualizarTela()
+
+		ev := termbox.PollEvent()
+		if ev.Type != termbox.EventKey {
+			continue
+		}
+		switch {
+		case ev.Key == termbox.KeyEsc:
+			return
+		case ev.Ch == 0 && teclasContem(jogo.Opcoes.Teclas[AcaoConsole], teclaNome(ev)):
+			return
+		case ev.Key == termbox.KeyEnter:
+			consoleEnviar(jogo, string(texto))
+			jogoProcessarEventos(jogo)
+			texto = nil
+			indiceHistorico = len(c.Historico)
+		case ev.Key == termbox.KeyTab:
+			linha, opcoes := consoleCompletar(jogo, string(texto))
+			texto = []rune(linha)
+			if len(opcoes) > 0 {
+				consoleEscrever(c, strings.Join(opcoes, "  "), CorTexto)
+			}
+		case ev.Key == termbox.KeyArrowUp:
+			if indiceHistorico > 0 {
+				indiceHistorico--
+				texto = []rune(c.Historico[indiceHistorico])
+			}
+		case ev.Key == termbox.KeyArrowDown:
+			if indiceHistorico < len(c.Historico)-1 {
+				indiceHistorico++
+				texto = []rune(c.Historico[indiceHistorico])
+			} else {
+				indiceHistorico = len(c.Historico)
+				texto = nil
+			}
+		case ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+			if len(texto) > 0 {
+				texto = texto[:len(texto)-1]
+			}
+		case ev.Key == termbox.KeySpace:
+			texto = append(texto, ' ')
+		case ev.Ch != 0:
+			texto = append(texto, ev.Ch)
+		}
+	}
+}
+
+// Desenha a saída recente, o prompt e a ajuda no topo da tela
+func interfaceDesenharConsole(c *Console, texto string) {
+	largura, _ := tela.Tamanho()
+	saida := c.Saida
+	if len(saida) > LinhasConsole {
+		saida = saida[len(saida)-LinhasConsole:]
+	}
+	linhas := append([]Indicador{}, saida...)
+	linhas = append(linhas,
+		Indicador{Texto: "> " + texto + "_", Cor: CorPadrao},
+		Indicador{Texto: traduzir("console.ajuda"), Cor: CorTexto})
+	for i, l := range linhas {
+		interfaceDesenharTexto(0, i, strings.Repeat(" ", largura), CorPadrao)
+		interfaceDesenharTexto(0, i, hudCortar(l.Texto, largura), l.Cor)
+	}
+}
@@ -0,0 +1,162 @@
+// constelacao.go - Registro das estrelas ativas e entrega de mensagens entre elas
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Alcance das mensagens difundidas por uma estrela, em tiles (distância Manhattan)
+const RaioVizinhancaEstrela = 12
+
+// Distância do personagem (Manhattan) até onde as estrelas são avisadas dos
+// movimentos dele; além dela a estrela não muda de estado por causa do personagem
+const RaioAvisoEstrelas = 4
+
+// Tempo em que a ligação entre duas estrelas fica desenhada após uma mensagem
+const DuracaoSinalEstrela = 2 * time.Second
+
+// Constelacao registra as estrelas em execução no nível. As estrelas consultam
+// o registro das suas próprias goroutines, por isso o acesso é sincronizado.
+type Constelacao struct {
+	sync.Mutex
+	estrelas map[string]*Star
+}
+
+func constelacaoNova() *Constelacao {
+	return &Constelacao{estrelas: make(map[string]*Star)}
+}
+
+func constelacaoRegistrar(c *Constelacao, star *Star) {
+	c.Lock()
+	c.estrelas[star.ID] = star
+	c.Unlock()
+}
+
+func constelacaoRemover(c *Constelacao, id string) {
+	c.Lock()
+	delete(c.estrelas, id)
+	c.Unlock()
+}
+
+// Estrelas registradas, ordenadas pelo identificador
+func constelacaoEstrelas(c *Constelacao) []*Star {
+	c.Lock()
+	defer c.Unlock()
+	estrelas := make([]*Star, 0, len(c.estrelas))
+	for _, s := range c.estrelas {
+		estrelas = append(estrelas, s)
+	}
+	sort.Slice(estrelas, func(i, j int) bool { return estrelas[i].ID < estrelas[j].ID })
+	return estrelas
+}
+
+// Estrelas a até RaioVizinhancaEstrela tiles da estrela dada, sem ela mesma
+func constelacaoVizinhas(c *Constelacao, origem *Star) []*Star {
+	var vizinhas []*Star
+	for _, s := range constelacaoEstrelas(c) {
+		if s.ID != origem.ID && abs(s.X-origem.X)+abs(s.Y-origem.Y) <= RaioVizinhancaEstrela {
+			vizinhas = append(vizinhas, s)
+		}
+	}
+	return vizinhas
+}
+
+// Entrega uma mensagem à estrela de destino sem bloquear o remetente.
+// Retorna false se a estrela não está registrada ou a fila dela está cheia.
+func constelacaoEnviar(c *Constelacao, msg StarCommunicationData) bool {
+	c.Lock()
+	destino, ok := c.estrelas[msg.ToStarID]
+	c.Unlock()
+	if !ok {
+		return false
+	}
+	select {
+	case destino.Comandos <- StarCommand{Type: "communicate", Target: msg.ToStarID, Data: msg}:
+		return true
+	default:
+		descartesRegistrar("star_message")
+		return false
+	}
+}
+
+// Envia a mensagem a todas as vizinhas do remetente; retorna quantas a receberam
+func constelacaoDifundir(c *Constelacao, origem *Star, msg StarCommunicationData) int {
+	entregues := 0
+	for _, s := range constelacaoVizinhas(c, origem) {
+		msg.ToStarID = s.ID
+		if constelacaoEnviar(c, msg) {
+			entregues++
+		}
+	}
+	return entregues
+}
+
+// Vizinha mais próxima do remetente, ou nil se ele está sozinho
+func constelacaoMaisProxima(c *Constelacao, origem *Star) *Star {
+	var maisProxima *Star
+	menor := 0
+	for _, s := range constelacaoVizinhas(c, origem) {
+		d := abs(s.X-origem.X) + abs(s.Y-origem.Y)
+		if maisProxima == nil || d < menor {
+			maisProxima, menor = s, d
+		}
+	}
+	return maisProxima
+}
+
+// SinalEstrela é uma mensagem recente entre duas estrelas, desenhada como uma linha
+type SinalEstrela struct {
+	De, Para Position
+	Mensagem string
+	Ate      time.Time
+}
+
+// Guarda o sinal para desenho e descarta os que já expiraram
+func jogoRegistrarSinal(jogo *Jogo, data StarCommunicationData) {
+	de, para := jogoEstrelaPorID(jogo, data.FromStarID), jogoEstrelaPorID(jogo, data.ToStarID)
+	agora := time.Now()
+	var ativos []SinalEstrela
+	for _, s := range jogo.Sinais {
+		if s.Ate.After(agora) {
+			ativos = append(ativos, s)
+		}
+	}
+	if de != nil && para != nil {
+		ativos = append(ativos, SinalEstrela{
+			De:       Position{X: de.X, Y: de.Y},
+			Para:     Position{X: para.X, Y: para.Y},
+			Mensagem: data.Message,
+			Ate:      agora.Add(DuracaoSinalEstrela),
+		})
+	}
+	jogo.Sinais = ativos
+}
+
+// Cor da linha de cada tipo de mensagem
+func sinalCor(mensagem string) Cor {
+	switch mensagem {
+	case "warning":
+		return CorVermelho
+	case "share_energy":
+		return CorVerde
+	}
+	return CorAmarelo
+}
+
+// Desenha as ligações das mensagens recentes nos tiles vazios e visíveis
+func interfaceDesenharSinais(jogo *Jogo) {
+	agora := time.Now()
+	for _, s := range jogo.Sinais {
+		if !s.Ate.After(agora) {
+			continue
+		}
+		for _, p := range linhaEntre(s.De.X, s.De.Y, s.Para.X, s.Para.Y) {
+			elem, ok := jogoElementoEm(jogo, p.X, p.Y)
+			if ok && elem == Vazio && jogoPosicaoVisivel(jogo, p.X, p.Y) {
+				interfaceMarcarNoMapa(jogo, p.X, p.Y, '·', sinalCor(s.Mensagem))
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// depuracao.go - Sobreposição de depuração com o estado interno do monstro e das estrelas
+package main
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Eventos descartados por canal cheio, por tipo; atualizado por várias goroutines
+var descartes = struct {
+	sync.Mutex
+	porTipo map[string]int
+}{porTipo: make(map[string]int)}
+
+func descartesRegistrar(tipo string) {
+	descartes.Lock()
+	descartes.porTipo[tipo]++
+	descartes.Unlock()
+}
+
+// Cópia dos contadores de descartes
+func descartesContagem() map[string]int {
+	descartes.Lock()
+	defer descartes.Unlock()
+	copia := make(map[string]int, len(descartes.porTipo))
+	for tipo, n := range descartes.porTipo {
+		copia[tipo] = n
+	}
+	return copia
+}
+
+// Envia um evento sem bloquear; com o canal cheio o evento é descartado e contado
+func enviarOuDescartar(out chan<- GameEvent, event GameEvent) bool {
+	select {
+	case out <- event:
+		return true
+	default:
+		descartesRegistrar(event.Type)
+		return false
+	}
+}
+
+// Posições que o monstro percorreria do ponto atual até o destino,
+// seguindo a mesma regra de passo usada no movimento
+func monsterPlanejarCaminho(origem, destino Position, estado MonsterState) []Position {
+	var caminho []Position
+	atual := origem
+	for i := 0; i < MaxPassosCaminho && atual != destino; i++ {
+		atual = monsterProximoPasso(atual, destino, estado)
+		caminho = append(caminho, atual)
+	}
+	return caminho
+}
+
+// Desenha a sobreposição de depuração sobre o mapa, com as cópias do estado
+// publicadas pelas goroutines das entidades
+func interfaceDesenharDepuracao(jogo *Jogo) {
+	if m := jogo.Monstro; m != nil {
+		s := m.snapshot()
+		interfaceDesenharVisaoMonstro(jogo, m.current_position)
+		caminho := jogoCaminhoMonstro(jogo)
+		destino := s.Destino
+		if s.Estado == Fleeing && len(caminho) > 0 {
+			// Em fuga o monstro segue a rota calculada pelo jogo
+			destino = caminho[len(caminho)-1]
+		}
+		for _, p := range caminho {
+			interfaceMarcarNoMapa(jogo, p.X, p.Y, '·', CorVermelho)
+		}
+		interfaceMarcarNoMapa(jogo, destino.X, destino.Y, 'X', CorVermelho)
+		interfaceMarcarNoMapa(jogo, s.UltimaVez.X, s.UltimaVez.Y, '?', CorAmarelo)
+		// Mostra o monstro mesmo fora do campo de visão; o personagem fica por cima dos marcadores
+		interfaceDesenharNoMapa(jogo, m.current_position.X, m.current_position.Y, jogoElementoMonstro(jogo))
+		interfaceDesenharNoMapa(jogo, jogo.PosX, jogo.PosY, jogo.elementoJogador())
+	}
+
+	for _, s := range jogo.Stars {
+		if tx, ty, ok := cameraParaTela(&jogo.Camera, s.X, s.Y); ok {
+			e := s.snapshot()
+			texto := traduzir("depuracao.estrela", estrelaEstadoNome(e.State), e.Energy, e.PulseCount)
+			interfaceDesenharTexto(tx+1, ty, texto, CorAmarelo)
+		}
+	}
+
+	interfaceDesenharPainelDepuracao(jogo)
+}
+
+// Marca a borda do raio de visão do monstro nos tiles vazios
+func interfaceDesenharVisaoMonstro(jogo *Jogo, centro Position) {
+	passos := int(math.Ceil(2 * math.Pi * MonsterRaioVisao))
+	for i := 0; i < passos; i++ {
+		angulo := 2 * math.Pi * float64(i) / float64(passos)
+		x := centro.X + int(math.Round(MonsterRaioVisao*math.Cos(angulo)))
+		y := centro.Y + int(math.Round(MonsterRaioVisao*math.Sin(angulo)))
+		if elem, ok := jogoElementoEm(jogo, x, y); ok && elem == Vazio {
+			interfaceMarcarNoMapa(jogo, x, y, '∙', CorCinzaEscuro)
+		}
+	}
+}
+
+// Desenha um marcador em uma posição do mapa, se ela estiver na câmera
+func interfaceMarcarNoMapa(jogo *Jogo, x, y int, simbolo rune, cor Cor) {
+	if tx, ty, ok := cameraParaTela(&jogo.Camera, x, y); ok {
+		interfaceDefinirCelula(tx, ty, simbolo, cor, CorPadrao)
+	}
+}
+
+// Painel no canto superior direito com goroutines, fila de eventos e descartes
+func interfaceDesenharPainelDepuracao(jogo *Jogo) {
+	linhas := []string{
+		traduzir("depuracao.goroutines", runtime.NumGoroutine()),
+		traduzir("depuracao.fila", len(jogo.GameEvents), cap(jogo.GameEvents)),
+	}
+	contagem := descartesContagem()
+	var tipos []string
+	total := 0
+	for tipo, n := range contagem {
+		tipos = append(tipos, tipo)
+		total += n
+	}
+	sort.Strings(tipos)
+	linhas = append(linhas, traduzir("depuracao.descartados", total))
+	for _, tipo := range tipos {
+		linhas = append(linhas, fmt.Sprintf("  %s: %d", tipo, contagem[tipo]))
+	}
+
+	largura, _ := tela.Tamanho()
+	maior := 0
+	for _, l := range linhas {
+		if n := len([]rune(l)); n > maior {
+			maior = n
+		}
+	}
+	x := largura - maior - 1
+	if x < 0 {
+		x = 0
+	}
+	for i, l := range linhas {
+		interfaceDesenharTexto(x, i, l, CorVerde)
+	}
+}
+
+func estrelaEstadoNome(estado StarState) string {
+	switch estado {
+	case StarInvisible:
+		return traduzir("depuracao.estrela.invisivel")
+	case StarPulsing:
+		return traduzir("depuracao.estrela.pulsando")
+	case StarCharging:
+		return traduzir("depuracao.estrela.carregando")
+	}
+	return traduzir("depuracao.estrela.visivel")
+}
@@ -0,0 +1,460 @@
+// editor.go - Editor de mapas no terminal (modo "jogo edit <mapa>")
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Limite de estados guardados para desfazer
+const EditorMaxHistorico = 100
+
+// Paleta de elementos que podem ser colocados no mapa, na ordem do registro de tiles
+func editorPaleta() []Elemento {
+	var paleta []Elemento
+	for _, t := range tiposTile {
+		if t.Paleta {
+			paleta = append(paleta, *t.Elemento)
+		}
+	}
+	return paleta
+}
+
+// Elementos por página da paleta, um para cada tecla de 1 a 9
+const EditorPaginaPaleta = 9
+
+// Número de páginas da paleta
+func editorPaginas() int {
+	return (len(editorPaleta()) + EditorPaginaPaleta - 1) / EditorPaginaPaleta
+}
+
+// Página da paleta em que está o elemento selecionado
+func editorPagina(ed *Editor) int {
+	return ed.Selecionado / EditorPaginaPaleta
+}
+
+// Passa para a página anterior (-1) ou seguinte (+1) da paleta, dando a volta
+// nas pontas, e seleciona o primeiro elemento dela
+func editorMudarPagina(ed *Editor, delta int) {
+	paginas := editorPaginas()
+	pagina := ((editorPagina(ed)+delta)%paginas + paginas) % paginas
+	ed.Selecionado = pagina * EditorPaginaPaleta
+}
+
+// Ferramentas do editor
+const (
+	FerramentaPincel    = "pincel"
+	FerramentaRetangulo = "retangulo"
+)
+
+// Nome da ferramenta no idioma atual
+func editorFerramentaNome(ferramenta string) string {
+	return traduzir("editor.ferramenta." + ferramenta)
+}
+
+type Editor struct {
+	Arquivo     string
+	Grade       [][]rune // conteúdo do mapa no mesmo formato do arquivo
+	Metadados   []string // linhas de metadados preservadas ao salvar
+	CursorX     int
+	CursorY     int
+	Selecionado int // índice do elemento selecionado na paleta
+	Ferramenta  string
+	Ancora      *Position // primeiro canto do retângulo em construção
+	Desfazer    [][][]rune
+	Refazer     [][][]rune
+	Modificado  bool
+	StatusMsg   string
+	Avisos      []string
+	Camera      Camera
+}
+
+// Lê o mapa como texto, sem interpretar os elementos
+func editorCarregar(nome string) (*Editor, error) {
+	ed := &Editor{
+		Arquivo:    nome,
+		Ferramenta: FerramentaPincel,
+		Camera:     cameraNova(CameraZonaMortaPadraoX, CameraZonaMortaPadraoY),
+	}
+
+	arq, err := os.Open(nome)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		// Arquivo novo: começa com um mapa vazio cercado por paredes
+		ed.Grade = editorGradeVazia(40, 15)
+		ed.Modificado = true
+		ed.StatusMsg = traduzir("editor.novo")
+		editorValidar(ed)
+		return ed, nil
+	}
+	defer arq.Close()
+
+	scanner := bufio.NewScanner(arq)
+	for scanner.Scan() {
+		linha := scanner.Text()
+		if len(linha) > 0 && linha[0] == PrefixoMetadados {
+			ed.Metadados = append(ed.Metadados, linha)
+			continue
+		}
+		ed.Grade = append(ed.Grade, []rune(linha))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(ed.Grade) == 0 {
+		ed.Grade = [][]rune{{Vazio.simbolo}}
+	}
+	editorValidar(ed)
+	return ed, nil
+}
+
+func editorGradeVazia(largura, altura int) [][]rune {
+	grade := make([][]rune, altura)
+	for y := range grade {
+		grade[y] = make([]rune, largura)
+		for x := range grade[y] {
+			if x == 0 || y == 0 || x == largura-1 || y == altura-1 {
+				grade[y][x] = Parede.simbolo
+			} else {
+				grade[y][x] = Vazio.simbolo
+			}
+		}
+	}
+	return grade
+}
+
+// Grava o mapa no formato lido por jogoCarregarMapa
+func editorSalvar(ed *Editor) error {
+	var sb strings.Builder
+	for _, linha := range ed.Grade {
+		sb.WriteString(string(linha))
+		sb.WriteByte('\n')
+	}
+	for _, linha := range ed.Metadados {
+		sb.WriteString(linha)
+		sb.WriteByte('\n')
+	}
+	if err := os.WriteFile(ed.Arquivo, []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+	ed.Modificado = false
+	return nil
+}
+
+// Retorna o símbolo na posição ou false se estiver fora do mapa
+func editorSimbolo(ed *Editor, x, y int) (rune, bool) {
+	if y < 0 || y >= len(ed.Grade) || x < 0 || x >= len(ed.Grade[y]) {
+		return 0, false
+	}
+	return ed.Grade[y][x], true
+}
+
+// Escreve um símbolo, estendendo a linha com espaços se necessário
+func editorDefinir(ed *Editor, x, y int, simbolo rune) {
+	if y < 0 || y >= len(ed.Grade) || x < 0 {
+		return
+	}
+	for len(ed.Grade[y]) <= x {
+		ed.Grade[y] = append(ed.Grade[y], Vazio.simbolo)
+	}
+	ed.Grade[y][x] = simbolo
+}
+
+// Guarda o estado atual antes de uma alteração
+func editorRegistrarHistorico(ed *Editor) {
+	ed.Desfazer = append(ed.Desfazer, editorCopiarGrade(ed.Grade))
+	if len(ed.Desfazer) > EditorMaxHistorico {
+		ed.Desfazer = ed.Desfazer[1:]
+	}
+	ed.Refazer = nil
+	ed.Modificado = true
+}
+
+func editorCopiarGrade(grade [][]rune) [][]rune {
+	copia := make([][]rune, len(grade))
+	for y, linha := range grade {
+		copia[y] = append([]rune(nil), linha...)
+	}
+	return copia
+}
+
+func editorDesfazer(ed *Editor) {
+	if len(ed.Desfazer) == 0 {
+		ed.StatusMsg = traduzir("editor.nada_desfazer")
+		return
+	}
+	ed.Refazer = append(ed.Refazer, editorCopiarGrade(ed.Grade))
+	ed.Grade = ed.Desfazer[len(ed.Desfazer)-1]
+	ed.Desfazer = ed.Desfazer[:len(ed.Desfazer)-1]
+	ed.Modificado = true
+	ed.StatusMsg = traduzir("editor.desfeito")
+}
+
+func editorRefazer(ed *Editor) {
+	if len(ed.Refazer) == 0 {
+		ed.StatusMsg = traduzir("editor.nada_refazer")
+		return
+	}
+	ed.Desfazer = append(ed.Desfazer, editorCopiarGrade(ed.Grade))
+	ed.Grade = ed.Refazer[len(ed.Refazer)-1]
+	ed.Refazer = ed.Refazer[:len(ed.Refazer)-1]
+	ed.Modificado = true
+	ed.StatusMsg = traduzir("editor.refeito")
+}
+
+// Coloca o elemento selecionado sob o cursor
+func editorColocar(ed *Editor, simbolo rune) {
+	if atual, ok := editorSimbolo(ed, ed.CursorX, ed.CursorY); ok && atual == simbolo {
+		return
+	}
+	editorRegistrarHistorico(ed)
+	editorDefinir(ed, ed.CursorX, ed.CursorY, simbolo)
+}
+
+// Preenche a região conectada de mesmo símbolo a partir do cursor
+func editorPreencher(ed *Editor, simbolo rune) {
+	alvo, ok := editorSimbolo(ed, ed.CursorX, ed.CursorY)
+	if !ok || alvo == simbolo {
+		return
+	}
+	editorRegistrarHistorico(ed)
+
+	pilha := []Position{{X: ed.CursorX, Y: ed.CursorY}}
+	for len(pilha) > 0 {
+		p := pilha[len(pilha)-1]
+		pilha = pilha[:len(pilha)-1]
+		if atual, ok := editorSimbolo(ed, p.X, p.Y); !ok || atual != alvo {
+			continue
+		}
+		ed.Grade[p.Y][p.X] = simbolo
+		pilha = append(pilha,
+			Position{X: p.X + 1, Y: p.Y}, Position{X: p.X - 1, Y: p.Y},
+			Position{X: p.X, Y: p.Y + 1}, Position{X: p.X, Y: p.Y - 1})
+	}
+}
+
+// Inicia ou conclui um retângulo entre a âncora e o cursor
+func editorRetangulo(ed *Editor, simbolo rune) {
+	if ed.Ancora == nil {
+		ed.Ancora = &Position{X: ed.CursorX, Y: ed.CursorY}
+		ed.StatusMsg = traduzir("editor.retangulo_inicio")
+		return
+	}
+
+	x0, x1 := ordenar(ed.Ancora.X, ed.CursorX)
+	y0, y1 := ordenar(ed.Ancora.Y, ed.CursorY)
+	ed.Ancora = nil
+
+	editorRegistrarHistorico(ed)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			editorDefinir(ed, x, y, simbolo)
+		}
+	}
+	ed.StatusMsg = traduzir("editor.retangulo", x1-x0+1, y1-y0+1)
+}
+
+func ordenar(a, b int) (int, int) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
+// Verifica problemas que impediriam o mapa de funcionar no jogo
+func editorValidar(ed *Editor) {
+	ed.Avisos = nil
+	personagens, inimigos := 0, 0
+	conhecidos := make(map[rune]bool)
+	for _, e := range editorPaleta() {
+		conhecidos[e.simbolo] = true
+	}
+
+	desconhecidos := 0
+	for _, linha := range ed.Grade {
+		for _, ch := range linha {
+			switch {
+			case ch == Personagem.simbolo:
+				personagens++
+			case ch == Inimigo.simbolo:
+				inimigos++
+			case !conhecidos[ch]:
+				desconhecidos++
+			}
+		}
+	}
+
+	switch {
+	case personagens == 0:
+		ed.Avisos = append(ed.Avisos, traduzir("editor.sem_personagem"))
+	case personagens > 1:
+		ed.Avisos = append(ed.Avisos, traduzir("editor.personagens", personagens))
+	}
+	if inimigos > 1 {
+		ed.Avisos = append(ed.Avisos, traduzir("editor.inimigos", inimigos))
+	}
+	if desconhecidos > 0 {
+		ed.Avisos = append(ed.Avisos, traduzir("editor.desconhecidos", desconhecidos))
+	}
+	if !editorBordaFechada(ed) {
+		ed.Avisos = append(ed.Avisos, traduzir("editor.borda"))
+	}
+}
+
+func editorBordaFechada(ed *Editor) bool {
+	ultima := len(ed.Grade) - 1
+	for y, linha := range ed.Grade {
+		if len(linha) == 0 {
+			return false
+		}
+		if y == 0 || y == ultima {
+			for _, ch := range linha {
+				if ch != Parede.simbolo {
+					return false
+				}
+			}
+			continue
+		}
+		if linha[0] != Parede.simbolo || linha[len(linha)-1] != Parede.simbolo {
+			return false
+		}
+	}
+	return true
+}
+
+func editorMoverCursor(ed *Editor, tecla rune) {
+	switch tecla {
+	case 'w':
+		ed.CursorY--
+	case 'a':
+		ed.CursorX--
+	case 's':
+		ed.CursorY++
+	case 'd':
+		ed.CursorX++
+	}
+
+	// Permite ir uma coluna além da maior linha para aumentar o mapa
+	largura := 0
+	for _, linha := range ed.Grade {
+		if len(linha) > largura {
+			largura = len(linha)
+		}
+	}
+	if ed.CursorX < 0 {
+		ed.CursorX = 0
+	}
+	if ed.CursorX > largura {
+		ed.CursorX = largura
+	}
+	if ed.CursorY < 0 {
+		ed.CursorY = 0
+	}
+	if ed.CursorY >= len(ed.Grade) {
+		ed.CursorY = len(ed.Grade) - 1
+	}
+}
+
+// Trata uma ação do editor; retorna false quando o editor deve ser fechado
+func editorExecutarAcao(ev EventoTeclado, ed *Editor) bool {
+	simbolo := editorPaleta()[ed.Selecionado].simbolo
+	ed.StatusMsg = ""
+
+	switch ev.Tipo {
+	case "sair":
+		if ed.Modificado {
+			ed.StatusMsg = traduzir("editor.nao_salvo")
+			return true
+		}
+		return false
+	case "descartar":
+		return false
+	case "salvar":
+		if err := editorSalvar(ed); err != nil {
+			ed.StatusMsg = traduzir("editor.erro_salvar", err)
+		} else {
+			ed.StatusMsg = traduzir("editor.salvo", ed.Arquivo)
+		}
+	case "desfazer":
+		editorDesfazer(ed)
+	case "refazer":
+		editorRefazer(ed)
+	case "mover":
+		editorMoverCursor(ed, ev.Tecla)
+	case "ferramenta":
+		switch ev.Tecla {
+		case ' ':
+			if ed.Ferramenta == FerramentaRetangulo {
+				editorRetangulo(ed, simbolo)
+			} else {
+				editorColocar(ed, simbolo)
+			}
+		case 'x':
+			editorColocar(ed, Vazio.simbolo)
+		case 'f':
+			editorPreencher(ed, simbolo)
+		case 'r':
+			ed.Ancora = nil
+			if ed.Ferramenta == FerramentaRetangulo {
+				ed.Ferramenta = FerramentaPincel
+			} else {
+				ed.Ferramenta = FerramentaRetangulo
+			}
+			ed.StatusMsg = traduzir("editor.ferramenta", editorFerramentaNome(ed.Ferramenta))
+		case 'u':
+			editorDesfazer(ed)
+		case 'y':
+			editorRefazer(ed)
+		case '\t':
+			ed.Selecionado = (ed.Selecionado + 1) % len(editorPaleta())
+		case '[':
+			editorMudarPagina(ed, -1)
+		case ']':
+			editorMudarPagina(ed, 1)
+		default:
+			// Os dígitos escolhem um elemento da página atual da paleta
+			if ev.Tecla >= '1' && ev.Tecla <= '9' {
+				indice := editorPagina(ed)*EditorPaginaPaleta + int(ev.Tecla-'1')
+				if indice < len(editorPaleta()) {
+					ed.Selecionado = indice
+				}
+			}
+		}
+	}
+
+	editorValidar(ed)
+	return true
+}
+
+// Loop principal do modo editor
+func editorExecutar(nome string) error {
+	ed, err := editorCarregar(nome)
+	if err != nil {
+		return err
+	}
+
+	interfaceIniciar()
+	defer interfaceFinalizar()
+
+	interfaceDesenharEditor(ed)
+	for {
+		evento := interfaceLerEventoEditor()
+		if continuar := editorExecutarAcao(evento, ed); !continuar {
+			return nil
+		}
+		interfaceDesenharEditor(ed)
+	}
+}
+
+// Elemento usado para desenhar um símbolo do arquivo no editor
+func editorElemento(simbolo rune) Elemento {
+	for _, e := range editorPaleta() {
+		if e.simbolo == simbolo {
+			return e
+		}
+	}
+	return Elemento{simbolo, CorVermelho, CorPadrao}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Editor com a grade dada, uma linha por string
+func editorTeste(linhas ...string) *Editor {
+	ed := &Editor{Ferramenta: FerramentaPincel, Camera: cameraNova(CameraZonaMortaPadraoX, CameraZonaMortaPadraoY)}
+	for _, linha := range linhas {
+		ed.Grade = append(ed.Grade, []rune(linha))
+	}
+	return ed
+}
+
+func editorTexto(ed *Editor) string {
+	var linhas []string
+	for _, linha := range ed.Grade {
+		linhas = append(linhas, string(linha))
+	}
+	return strings.Join(linhas, "\n")
+}
+
+// Índice do elemento na paleta
+func editorIndicePaleta(t *testing.T, e Elemento) int {
+	t.Helper()
+	for i, p := range editorPaleta() {
+		if p.simbolo == e.simbolo {
+			return i
+		}
+	}
+	t.Fatalf("%q fora da paleta", e.simbolo)
+	return -1
+}
+
+func TestEditorPreencher(t *testing.T) {
+	casos := []struct {
+		nome     string
+		x, y     int
+		simbolo  rune
+		esperado []string
+	}{
+		{"região fechada", 1, 1, '♣', []string{
+			"▤▤▤▤▤▤",
+			"▤♣♣▤ ▤",
+			"▤♣▤▤ ▤",
+			"▤▤▤▤▤▤",
+		}},
+		{"outra região", 4, 2, '≈', []string{
+			"▤▤▤▤▤▤",
+			"▤  ▤≈▤",
+			"▤ ▤▤≈▤",
+			"▤▤▤▤▤▤",
+		}},
+		{"paredes ligadas à borda", 0, 0, ' ', []string{
+			"      ",
+			"      ",
+			"      ",
+			"      ",
+		}},
+		{"mesmo símbolo não muda nada", 1, 1, ' ', []string{
+			"▤▤▤▤▤▤",
+			"▤  ▤ ▤",
+			"▤ ▤▤ ▤",
+			"▤▤▤▤▤▤",
+		}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			ed := editorTeste("▤▤▤▤▤▤", "▤  ▤ ▤", "▤ ▤▤ ▤", "▤▤▤▤▤▤")
+			ed.CursorX, ed.CursorY = c.x, c.y
+			editorPreencher(ed, c.simbolo)
+			if got, esperado := editorTexto(ed), strings.Join(c.esperado, "\n"); got != esperado {
+				t.Errorf("grade:\n%s\nesperada:\n%s", got, esperado)
+			}
+		})
+	}
+}
+
+func TestEditorRetangulo(t *testing.T) {
+	ed := editorTeste("▤▤▤▤▤▤", "▤    ▤", "▤    ▤", "▤    ▤", "▤▤▤▤▤▤")
+	ed.Ferramenta = FerramentaRetangulo
+	ed.Selecionado = editorIndicePaleta(t, Vegetacao)
+
+	ed.CursorX, ed.CursorY = 3, 3
+	editorExecutarAcao(EventoTeclado{Tipo: "ferramenta", Tecla: ' '}, ed)
+	if ed.Ancora == nil || *ed.Ancora != (Position{X: 3, Y: 3}) {
+		t.Fatalf("âncora = %v, esperada (3, 3)", ed.Ancora)
+	}
+	// O retângulo vale em qualquer direção a partir da âncora
+	ed.CursorX, ed.CursorY = 2, 1
+	editorExecutarAcao(EventoTeclado{Tipo: "ferramenta", Tecla: ' '}, ed)
+	esperado := strings.Join([]string{"▤▤▤▤▤▤", "▤ ♣♣ ▤", "▤ ♣♣ ▤", "▤ ♣♣ ▤", "▤▤▤▤▤▤"}, "\n")
+	if got := editorTexto(ed); got != esperado {
+		t.Errorf("grade:\n%s\nesperada:\n%s", got, esperado)
+	}
+	if ed.Ancora != nil {
+		t.Error("âncora mantida depois de concluir o retângulo")
+	}
+
+	// Um retângulo inteiro é desfeito de uma vez
+	editorDesfazer(ed)
+	if got := editorTexto(ed); strings.ContainsRune(got, '♣') {
+		t.Errorf("retângulo não desfeito:\n%s", got)
+	}
+}
+
+func TestEditorDesfazerRefazer(t *testing.T) {
+	ed := editorTeste("▤▤▤▤", "▤  ▤", "▤▤▤▤")
+	estados := []string{editorTexto(ed)}
+	ed.CursorX, ed.CursorY = 1, 1
+	editorColocar(ed, '♣')
+	estados = append(estados, editorTexto(ed))
+	ed.CursorX = 2
+	editorColocar(ed, '≈')
+	estados = append(estados, editorTexto(ed))
+
+	passos := []struct {
+		acao   func(*Editor)
+		estado int
+	}{
+		{editorDesfazer, 1},
+		{editorDesfazer, 0},
+		{editorDesfazer, 0}, // sem histórico, nada muda
+		{editorRefazer, 1},
+		{editorRefazer, 2},
+		{editorRefazer, 2},
+		{editorDesfazer, 1},
+	}
+	for i, p := range passos {
+		p.acao(ed)
+		if got := editorTexto(ed); got != estados[p.estado] {
+			t.Fatalf("passo %d: grade\n%s\nesperada\n%s", i, got, estados[p.estado])
+		}
+	}
+
+	// Uma edição nova descarta o que havia para refazer
+	ed.CursorX = 2
+	editorColocar(ed, '▣')
+	editorRefazer(ed)
+	if got := editorTexto(ed); !strings.ContainsRune(got, '▣') || strings.ContainsRune(got, '≈') {
+		t.Errorf("refazer depois de uma edição nova mudou a grade:\n%s", got)
+	}
+}
+
+func TestEditorPaleta(t *testing.T) {
+	total := len(editorPaleta())
+	if total <= EditorPaginaPaleta {
+		t.Skipf("paleta com %d elementos cabe em uma página", total)
+	}
+	ed := editorTeste("▤")
+	tecla := func(r rune) { editorExecutarAcao(EventoTeclado{Tipo: "ferramenta", Tecla: r}, ed) }
+
+	tecla('3')
+	if ed.Selecionado != 2 {
+		t.Errorf("3 na primeira página selecionou %d", ed.Selecionado)
+	}
+	tecla(']')
+	if ed.Selecionado != EditorPaginaPaleta {
+		t.Errorf("] selecionou %d, esperado o primeiro da segunda página", ed.Selecionado)
+	}
+	tecla('2')
+	if ed.Selecionado != EditorPaginaPaleta+1 {
+		t.Errorf("2 na segunda página selecionou %d", ed.Selecionado)
+	}
+
+	// A última página pode ter menos elementos; dígitos além dela são ignorados
+	ultima := (editorPaginas() - 1) * EditorPaginaPaleta
+	tecla('[')
+	tecla('[')
+	if ed.Selecionado != ultima {
+		t.Fatalf("[ a partir da primeira página selecionou %d, esperado %d", ed.Selecionado, ultima)
+	}
+	if restantes := total - ultima; restantes < EditorPaginaPaleta {
+		tecla(rune('1' + restantes))
+		if ed.Selecionado != ultima {
+			t.Errorf("dígito além da última página selecionou %d", ed.Selecionado)
+		}
+	}
+
+	// Tab percorre a paleta inteira, passando de página
+	ed.Selecionado = EditorPaginaPaleta - 1
+	tecla('\t')
+	if ed.Selecionado != EditorPaginaPaleta || editorPagina(ed) != 1 {
+		t.Errorf("Tab no fim da primeira página selecionou %d", ed.Selecionado)
+	}
+}
+
+func TestEditorRodapeCabeNaTela(t *testing.T) {
+	ed := editorTeste("▤▤▤", "▤☺▤", "▤▤▤")
+	ed.Arquivo = "um-nome-de-arquivo-bem-comprido-para-o-rodape.txt"
+	ed.StatusMsg = strings.Repeat("status ", 20)
+	ed.Selecionado = EditorPaginaPaleta + 1
+	for _, largura := range []int{80, 32} {
+		mem := NovaTelaMemoria(largura, 20)
+		anterior := tela
+		tela = mem
+		interfaceDesenharEditor(ed)
+		tela = anterior
+
+		topo := 20 - AlturaHUDEditor
+		linha := []rune(mem.Linha(topo))
+		if !strings.HasPrefix(string(linha), "2/") {
+			t.Errorf("largura %d: rodapé sem a página atual: %q", largura, string(linha))
+		}
+		// Nenhum elemento fica pela metade: um rótulo é sempre seguido do símbolo
+		if linha[len(linha)-1] == ':' {
+			t.Errorf("largura %d: rótulo sem o elemento no fim da linha: %q", largura, string(linha))
+		}
+		if largura == 80 {
+			paleta := editorPaleta()
+			segundo := temaAparencia(paleta[EditorPaginaPaleta+1]).Simbolo
+			if !strings.Contains(string(linha), "2:"+string(segundo)) {
+				t.Errorf("rodapé sem o segundo elemento da página 2: %q", string(linha))
+			}
+		}
+		if got := len([]rune(mem.Linha(topo + 1))); got > largura {
+			t.Errorf("largura %d: status com %d colunas", largura, got)
+		}
+	}
+}
+
+func TestEditorSalvarECarregar(t *testing.T) {
+	nome := filepath.Join(t.TempDir(), "novo.txt")
+	ed, err := editorCarregar(nome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ed.Modificado || len(ed.Grade) == 0 {
+		t.Fatal("arquivo novo sem a grade inicial")
+	}
+	ed.Metadados = []string{"@gerador estrela 5 1"}
+
+	colocar := func(e Elemento, x, y int) {
+		ed.Selecionado = editorIndicePaleta(t, e)
+		ed.CursorX, ed.CursorY = x, y
+		editorExecutarAcao(EventoTeclado{Tipo: "ferramenta", Tecla: ' '}, ed)
+	}
+	colocar(Personagem, 2, 3)
+	colocar(Inimigo, 10, 5)
+	colocar(Vegetacao, 4, 4)
+	editorExecutarAcao(EventoTeclado{Tipo: "salvar"}, ed)
+	if ed.Modificado {
+		t.Fatalf("mapa não salvo: %s", ed.StatusMsg)
+	}
+
+	jogo := jogoNovo()
+	if err := jogoCarregarMapa(nome, &jogo); err != nil {
+		t.Fatal(err)
+	}
+	if jogo.PosX != 2 || jogo.PosY != 3 {
+		t.Errorf("personagem em (%d, %d), esperado (2, 3)", jogo.PosX, jogo.PosY)
+	}
+	if jogo.Monstro == nil || jogo.Monstro.current_position != (Position{X: 10, Y: 5}) {
+		t.Errorf("monstro carregado %+v, esperado em (10, 5)", jogo.Monstro)
+	}
+	if jogo.Mapa[4][4] != Vegetacao {
+		t.Errorf("tile (4, 4) = %q, esperada vegetação", jogo.Mapa[4][4].simbolo)
+	}
+	if len(jogo.Geradores) != 1 || jogo.Geradores[0].Item != GeradorEstrela {
+		t.Errorf("metadados não preservados: %+v", jogo.Geradores)
+	}
+
+	// O arquivo salvo abre no editor como foi gravado
+	reaberto, err := editorCarregar(nome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if editorTexto(reaberto) != editorTexto(ed) || len(reaberto.Metadados) != 1 || len(reaberto.Avisos) != 0 {
+		t.Errorf("reaberto diferente: avisos %v\n%s", reaberto.Avisos, editorTexto(reaberto))
+	}
+}
@@ -15,6 +15,7 @@ type InvisibilityApplied struct {
 	Duration int
 }
 
+// Espera a coleta do item, entregue pelo jogo no canal dele
 func (i *Invisibility) Run(ctx context.Context, out chan<- GameEvent, picked <-chan PlayerCollect) {
 	for {
 		select {
@@ -28,26 +29,21 @@ func (i *Invisibility) Run(ctx context.Context, out chan<- GameEvent, picked <-c
 				continue
 			}
 
+			// O buff vai para o inventário do jogador e é aplicado quando ele usar o item
 			out <- GameEvent{
 				Type: EventRemoveElement,
 				Data: Invisibility{X: i.X, Y: i.Y},
 			}
 
-			// Aplica o buff de invisibilidade ao jogador
-			out <- GameEvent{
-				Type: EventApplyInvisibility,
-				Data: InvisibilityApplied{Duration: InvisibilityDuration},
-			}
-
 			// Item é one-shot
 			return
 		}
 	}
 }
 
-// Remoção do item “sob” o jogador quando consumido
+// Remoção do item “sob” o jogador quando guardado no inventário
 func ConsumirItemInvisibilidade(jogo *Jogo) bool {
-	if jogo.UltimoVisitado.simbolo == InvisibilityItem.simbolo {
+	if personagemGuardarItem(jogo, ItemInvisibilidade) {
 		jogo.UltimoVisitado = Vazio
 		return true
 	}
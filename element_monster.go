@@ -3,24 +3,104 @@ package main
 import (
 	"context"
 	"math"
-	"math/rand"
 	"time"
 )
 
-func (m *Monster) Run(ctx context.Context, out chan<- GameEvent, alerts <-chan PlayerAlert, pstate <-chan PlayerState) {
+// Cópia do estado interno do monstro. A goroutine dele a publica a cada
+// iteração do loop; o jogo lê a cópia para salvar, desenhar e exportar, sem
+// disputar os campos que a goroutine altera.
+type MonsterSnapshot struct {
+	Destino    Position
+	UltimaVez  Position
+	Estado     MonsterState
+	ShiftCount int
+	Fuga       []Position // rota de fuga seguida
+	Rota       []Position // rota recebida do jogo para o destino atual
+}
+
+// Publica a cópia do estado; chamada pela goroutine do monstro ou antes de ela começar
+func (m *Monster) publishSnapshot() {
+	s := MonsterSnapshot{
+		Destino:    m.destiny_position,
+		UltimaVez:  m.last_seen,
+		Estado:     m.state,
+		ShiftCount: m.shift_count,
+		Fuga:       append([]Position(nil), m.flee_path...),
+	}
+	if m.route_target == m.destiny_position {
+		s.Rota = append([]Position(nil), m.route...)
+	}
+	m.mu.Lock()
+	m.publicado = s
+	m.mu.Unlock()
+}
+
+// Última cópia publicada do estado do monstro
+func (m *Monster) snapshot() MonsterSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.publicado
+}
+
+func (m *Monster) Run(ctx context.Context, out chan<- GameEvent, alerts <-chan PlayerAlert, pstate <-chan PlayerState, pausa <-chan bool) {
 	// Timer para controlar velocidade do monstro
-	ticker := time.NewTicker(30 * time.Millisecond)
+	ticker := time.NewTicker(duracaoJogo(30 * time.Millisecond))
 	defer ticker.Stop()
 
 	// Timeout para comportamento alternativo se não receber posição do jogador
-	playerTimeout := time.NewTimer(3 * time.Second)
+	playerTimeout := time.NewTimer(duracaoJogo(3 * time.Second))
 	defer playerTimeout.Stop()
 
+	// Reaparecimento depois da captura; parado enquanto o monstro está solto
+	respawnTimer := time.NewTimer(duracaoJogo(MonsterRespawnDelay))
+	if m.state != Caught {
+		respawnTimer.Stop()
+	}
+	defer respawnTimer.Stop()
+
+	estadoAnterior := m.state
+	informado := MonsterStateData{MonsterID: m.id, State: m.state, Epoca: m.epoca}
 	for {
+		m.publishSnapshot()
+
+		if m.state != estadoAnterior {
+			if m.state == Caught {
+				reiniciarTimer(respawnTimer, duracaoJogo(MonsterRespawnDelay))
+			}
+			estadoAnterior = m.state
+		}
+
+		// Pede ao jogo para levá-lo à origem até o pedido ser entregue; o estado
+		// novo só é informado depois, para o jogo não soltar o monstro onde ele
+		// foi capturado
+		if m.respawning && m.sendRespawn(out) {
+			m.respawning = false
+		}
+
+		// Avisa o jogo sempre que o estado ou a época mudou. Com o canal cheio o
+		// aviso é descartado e o último informado fica como estava, para tentar
+		// de novo na próxima iteração.
+		atual := MonsterStateData{MonsterID: m.id, State: m.state, Epoca: m.epoca}
+		if !m.respawning && atual != informado && enviarOuDescartar(out, GameEvent{Type: "monster_state", Data: atual}) {
+			informado = atual
+		}
+
 		select {
 		case <-ctx.Done():
 			return
 
+		case pausado := <-pausa:
+			// Congela o monstro até o jogo ser retomado; o timeout recomeça do zero
+			if pausado {
+				if !aguardarRetomada(ctx, pausa) {
+					return
+				}
+				reiniciarTimer(playerTimeout, duracaoJogo(3*time.Second))
+				if m.state == Caught {
+					reiniciarTimer(respawnTimer, duracaoJogo(MonsterRespawnDelay))
+				}
+			}
+
 		case playerState := <-pstate:
 			m.updatePlayerPosition(playerState)
 
@@ -31,10 +111,18 @@ func (m *Monster) Run(ctx context.Context, out chan<- GameEvent, alerts <-chan P
 				default:
 				}
 			}
-			playerTimeout.Reset(3 * time.Second)
+			playerTimeout.Reset(duracaoJogo(3 * time.Second))
+
+		case <-respawnTimer.C:
+			m.respawn()
 
 		case <-playerTimeout.C:
 			// Comportamento alternativo 3seg TIMEOUT: entrar em modo "alerta" e patrulhar agressivamente
+			if m.state == Fleeing || m.state == Caught {
+				// Assustado ou capturado, o monstro não muda de comportamento
+				playerTimeout.Reset(duracaoJogo(3 * time.Second))
+				break
+			}
 			if m.state == Hunting {
 				// Se estava caçando, voltar para patrulha mas com comportamento diferente
 				m.state = Patrolling
@@ -47,20 +135,16 @@ func (m *Monster) Run(ctx context.Context, out chan<- GameEvent, alerts <-chan P
 				Type: "monster_timeout",
 				Data: map[string]interface{}{
 					"monster_id": m.id,
-					"message":    "Monster lost track of player - entering aggressive patrol",
 				},
 			}
 
-			select {
-			case out <- timeoutEvent:
-			default:
-			}
+			enviarOuDescartar(out, timeoutEvent)
 
-			playerTimeout.Reset(3 * time.Second)
+			playerTimeout.Reset(duracaoJogo(3 * time.Second))
 
 		case alert := <-alerts:
 			select {
-			case <-time.After(500 * time.Millisecond):
+			case <-time.After(duracaoJogo(500 * time.Millisecond)):
 				// Timeout ao processar alerta - comportamento alternativo
 				m.state = Patrolling
 				m.generateRandomDestiny()
@@ -78,9 +162,26 @@ func (m *Monster) Run(ctx context.Context, out chan<- GameEvent, alerts <-chan P
 
 // Controla velocidade: monstro move SEMPRE quando caçando
 func (m *Monster) shouldMove() bool {
+	if m.stuck_ticks > 0 {
+		// Preso na lama
+		m.stuck_ticks--
+		return false
+	}
 	if m.state == Hunting {
 		return true
 	}
+	if m.state == Caught {
+		return false
+	}
+	if m.state == Fleeing {
+		// Assustado, anda um tique em cada MonsterFleeTicks
+		m.shift_count++
+		if m.shift_count >= MonsterFleeTicks {
+			m.shift_count = 0
+			return true
+		}
+		return false
+	}
 
 	m.shift_count++
 	if m.shift_count >= 1 {
@@ -92,6 +193,11 @@ func (m *Monster) shouldMove() bool {
 func (m *Monster) updatePlayerPosition(playerState PlayerState) {
 	playerPos := Position(playerState)
 
+	// Assustado ou capturado, o monstro não persegue o jogador
+	if m.state == Fleeing || m.state == Caught {
+		return
+	}
+
 	// Calcula distância até o jogador
 	if m.canSeePlayer(playerPos) {
 		m.state = Hunting
@@ -111,13 +217,29 @@ func (m *Monster) updatePlayerPosition(playerState PlayerState) {
 	}
 } // Executa movimento baseado no estado atual
 func (m *Monster) processMovement(out chan<- GameEvent) {
+	// Enquanto o jogo não o leva à origem, o monstro espera
+	if m.respawning {
+		return
+	}
+
 	// Se está patrulhando e chegou no destino, gerar novo destino
 	if m.state == Patrolling && m.distanceTo(m.destiny_position) < 1 {
 		m.generateRandomDestiny()
 	}
 
-	oldX, oldY := m.current_position.X, m.current_position.Y
-	newPos := m.calculateNextPosition(m.destiny_position)
+	atual := m.posicao()
+	oldX, oldY := atual.X, atual.Y
+	var newPos Position
+	if m.state == Fleeing {
+		// Segue a rota de fuga recebida do jogo; sem rota, fica parado
+		if len(m.flee_path) == 0 {
+			return
+		}
+		newPos = m.flee_path[0]
+		m.flee_path = m.flee_path[1:]
+	} else {
+		newPos = m.nextRouteStep(out)
+	}
 
 	event := GameEvent{
 		Type: "monster_move",
@@ -130,36 +252,115 @@ func (m *Monster) processMovement(out chan<- GameEvent) {
 		},
 	}
 
-	select {
-	case out <- event:
-	default:
-		// Canal cheio, pular este evento
-	}
+	// Canal cheio: o evento é descartado e o monstro tenta de novo no próximo tique
+	enviarOuDescartar(out, event)
+}
+
+// Distância até onde o monstro enxerga o jogador
+const MonsterRaioVisao = 25.0
+
+// Espera do monstro capturado antes de reaparecer na origem
+const MonsterRespawnDelay = 5 * time.Second
+
+// Tiques do relógio do monstro por passo enquanto ele foge
+const MonsterFleeTicks = 4
+
+// Retoma a patrulha depois da captura. Quem leva o monstro à origem é o jogo,
+// ao tratar o evento monster_respawn, como faz com os movimentos; ao chegar,
+// o monstro sorteia o próximo destino.
+func (m *Monster) respawn() {
+	m.state = Patrolling
+	m.destiny_position = m.origin
+	m.route, m.flee_path = nil, nil
+	m.route_asked = Position{X: -1, Y: -1}
+	m.respawning = true
+}
+
+// Pede ao jogo para levar o monstro à origem; retorna se o pedido foi entregue
+func (m *Monster) sendRespawn(out chan<- GameEvent) bool {
+	atual := m.posicao()
+	return enviarOuDescartar(out, GameEvent{
+		Type: "monster_respawn",
+		Data: MonsterMoveData{OldX: atual.X, OldY: atual.Y, NewX: m.origin.X, NewY: m.origin.Y, MonsterID: m.id},
+	})
+}
+
+// Posição do monstro, lida pela goroutine dele enquanto o jogo pode mudá-la
+func (m *Monster) posicao() Position {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.current_position
+}
+
+// Move o monstro; só o jogo muda a posição dele
+func (m *Monster) definirPosicao(p Position) {
+	m.mu.Lock()
+	m.current_position = p
+	m.mu.Unlock()
 }
 
 // Verifica se pode ver o jogador
 func (m *Monster) canSeePlayer(playerPos Position) bool {
 	distance := m.distanceTo(playerPos)
-	return distance <= 25.0
+	return distance <= MonsterRaioVisao
 }
 
 // Calcula distância euclidiana entre monstro e uma posição
 func (m *Monster) distanceTo(pos Position) float64 {
-	dx := float64(m.current_position.X - pos.X)
-	dy := float64(m.current_position.Y - pos.Y)
+	atual := m.posicao()
+	dx := float64(atual.X - pos.X)
+	dy := float64(atual.Y - pos.Y)
 	return math.Sqrt(dx*dx + dy*dy)
 }
 
+// Próximo passo até o destino: pela rota calculada pelo jogo, que atravessa
+// portais e contorna paredes, ou pelo passo direto enquanto ela não chega. Uma
+// rota nova é pedida a cada destino novo; o passo só sai da rota quando o jogo
+// aceita o movimento, então um movimento descartado é tentado de novo.
+func (m *Monster) nextRouteStep(out chan<- GameEvent) Position {
+	if m.route_target != m.destiny_position {
+		m.route = nil
+	}
+	atual := m.posicao()
+	for len(m.route) > 0 && m.route[0] == atual {
+		m.route = m.route[1:]
+	}
+	if len(m.route) > 0 {
+		if monsterAdjacente(m.route[0], atual) {
+			return m.route[0]
+		}
+		// O monstro saiu da rota (lama, buraco, console): pede outra
+		m.route = nil
+		m.route_asked = Position{X: -1, Y: -1}
+	}
+	if m.route_asked != m.destiny_position {
+		pedido := GameEvent{Type: "monster_route", Data: MonsterRouteData{MonsterID: m.id, Para: m.destiny_position}}
+		if enviarOuDescartar(out, pedido) {
+			m.route_asked = m.destiny_position
+		}
+	}
+	return m.calculateNextPosition(m.destiny_position)
+}
+
+// Informa se b está a um passo de a, inclusive na diagonal
+func monsterAdjacente(a, b Position) bool {
+	return a != b && abs(a.X-b.X) <= 1 && abs(a.Y-b.Y) <= 1
+}
+
 func (m *Monster) calculateNextPosition(target Position) Position {
-	currentPos := m.current_position
+	return monsterProximoPasso(m.posicao(), target, m.state)
+}
 
+// Próxima posição a partir de currentPos em direção ao alvo; caçando, o
+// monstro anda na diagonal, patrulhando, primeiro na horizontal
+func monsterProximoPasso(currentPos, target Position, state MonsterState) Position {
 	// Calcula direção
 	dx := target.X - currentPos.X
 	dy := target.Y - currentPos.Y
 
 	newPos := currentPos
 
-	if m.state == Hunting {
+	if state == Hunting {
 		// Prioriza movimento diagonal quando possível pois é mais rapido
 		if dx != 0 && dy != 0 {
 			if dx > 0 {
@@ -205,14 +406,15 @@ func (m *Monster) calculateNextPosition(target Position) Position {
 }
 func (m *Monster) generateRandomDestiny() {
 	radius := 10
+	atual := m.posicao()
 	maxTries := 10
 
 	for tries := 0; tries < maxTries; tries++ {
-		angle := rand.Float64() * 2 * math.Pi
-		distance := rand.Float64() * float64(radius)
+		angle := m.rng.Float64() * 2 * math.Pi
+		distance := m.rng.Float64() * float64(radius)
 
-		newX := m.current_position.X + int(distance*math.Cos(angle))
-		newY := m.current_position.Y + int(distance*math.Sin(angle))
+		newX := atual.X + int(distance*math.Cos(angle))
+		newY := atual.Y + int(distance*math.Sin(angle))
 
 		// Verificar se está dentro dos limites do mapa
 		if newX >= 1 && newX < 79 && newY >= 1 && newY < 29 {
@@ -222,21 +424,22 @@ func (m *Monster) generateRandomDestiny() {
 	}
 
 	m.destiny_position = Position{
-		X: m.current_position.X + (rand.Intn(3) - 1), // -1, 0, ou 1
-		Y: m.current_position.Y + (rand.Intn(3) - 1), // -1, 0, ou 1
+		X: atual.X + (m.rng.Intn(3) - 1), // -1, 0, ou 1
+		Y: atual.Y + (m.rng.Intn(3) - 1), // -1, 0, ou 1
 	}
 }
 
 func (m *Monster) generateAggressivePatrolDestiny() {
 	radius := 15
+	atual := m.posicao()
 	maxTries := 10
 
 	for tries := 0; tries < maxTries; tries++ {
-		angle := rand.Float64() * 2 * math.Pi
-		distance := rand.Float64() * float64(radius)
+		angle := m.rng.Float64() * 2 * math.Pi
+		distance := m.rng.Float64() * float64(radius)
 
-		newX := m.current_position.X + int(distance*math.Cos(angle))
-		newY := m.current_position.Y + int(distance*math.Sin(angle))
+		newX := atual.X + int(distance*math.Cos(angle))
+		newY := atual.Y + int(distance*math.Sin(angle))
 
 		if newX >= 1 && newX < 79 && newY >= 1 && newY < 29 {
 			m.destiny_position = Position{X: newX, Y: newY}
@@ -245,14 +448,61 @@ func (m *Monster) generateAggressivePatrolDestiny() {
 	}
 
 	m.destiny_position = Position{
-		X: m.current_position.X + (rand.Intn(5) - 2), // -2 a 2
-		Y: m.current_position.Y + (rand.Intn(5) - 2), // -2 a 2
+		X: atual.X + (m.rng.Intn(5) - 2), // -2 a 2
+		Y: atual.Y + (m.rng.Intn(5) - 2), // -2 a 2
 	}
 }
 
 // Processa alertas recebidos
 func (m *Monster) processAlert(alert PlayerAlert) {
+	if alert.Epoca > m.epoca {
+		m.epoca = alert.Epoca
+	}
+	// Capturado, o monstro só volta pelo reaparecimento ou pelo console;
+	// assustado, ignora avistamentos e barulhos
+	if m.state == Caught && alert.Type != "set_state" {
+		return
+	}
+	if m.state == Fleeing && (alert.Type == "player_nearby" || alert.Type == "noise") {
+		return
+	}
 	switch alert.Type {
+	case "power_start":
+		// O personagem ganhou o poder: foge pela rota que o jogo enviar
+		m.state = Fleeing
+		m.flee_path = nil
+	case "power_end":
+		if m.state == Fleeing {
+			m.state = Patrolling
+			m.flee_path = nil
+			m.generateRandomDestiny()
+		}
+	case "flee":
+		if path, ok := alert.Data.([]Position); ok && m.state == Fleeing {
+			m.flee_path = path
+		}
+	case "route":
+		// Rotas de um destino que o monstro já trocou são descartadas
+		if rota, ok := alert.Data.(MonsterRouteData); ok && rota.Para == m.destiny_position {
+			m.route, m.route_target = rota.Caminho, rota.Para
+		}
+	case "mud":
+		m.stuck_ticks = MonsterMudTicks
+	case "respawn":
+		// O jogo levou o monstro à origem (buraco, chamas): perde o rastro do
+		// personagem e recomeça a patrulha de lá; assustado, espera a próxima rota de fuga
+		m.route, m.flee_path = nil, nil
+		m.route_asked = Position{X: -1, Y: -1}
+		m.stuck_ticks = 0
+		if m.state == Hunting {
+			m.state = Patrolling
+		}
+		if m.state == Patrolling {
+			m.destiny_position = m.origin
+		}
+	case "caught":
+		m.state = Caught
+		m.flee_path = nil
 	case "player_nearby":
 		// Jogador detectado próximo
 		if data, ok := alert.Data.(map[string]int); ok {
@@ -273,6 +523,14 @@ func (m *Monster) processAlert(alert PlayerAlert) {
 				}
 			}
 		}
+	case "set_state":
+		// Estado imposto pelo console de desenvolvimento
+		if state, ok := alert.Data.(MonsterState); ok {
+			m.state = state
+			if state == Patrolling {
+				m.generateRandomDestiny()
+			}
+		}
 	default:
 		m.generateRandomDestiny()
 	}
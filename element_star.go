@@ -4,6 +4,7 @@ package main
 import (
 	"context"
 	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -64,10 +65,9 @@ type StarChargedData struct {
 }
 
 type StarTimeoutData struct {
-	X, Y    int
-	Message string
-	Action  string
-	StarID  string
+	X, Y   int
+	Action string
+	StarID string
 }
 
 type StarCommunicationData struct {
@@ -83,7 +83,9 @@ type StarCommand struct {
 	Data   interface{}
 }
 
-// Estrutura da estrela
+// Estrutura da estrela. Os avisos ao jogo (mudanças de estado, pulsos,
+// mensagens) são descartados com o canal cheio, para a estrela nunca ficar
+// presa esperando o jogo; só a coleta espera ser entregue.
 type Star struct {
 	X, Y          int
 	State         StarState
@@ -93,11 +95,41 @@ type Star struct {
 	PulseCount    int
 	LastPlayerPos Position
 	MapAccess     chan chan bool
+	Comandos      chan StarCommand   // comandos endereçados a esta estrela
+	Coletas       chan PlayerCollect // coleta desta estrela, entregue pelo jogo
+	Constelacao   *Constelacao       // registro para falar com as outras estrelas (nil fora do jogo)
+	rng           *rand.Rand         // gerador próprio, para não disputar o global com outras goroutines
+
+	mu        sync.Mutex   // protege publicado
+	publicado StarSnapshot // cópia do estado lida pelo jogo
+}
+
+// Cópia do estado da estrela, publicada pela goroutine dela a cada iteração
+// do loop; o jogo lê a cópia para salvar e desenhar
+type StarSnapshot struct {
+	State      StarState
+	IsVisible  bool
+	Energy     int
+	PulseCount int
+}
+
+// Publica a cópia do estado; chamada pela goroutine da estrela ou antes de ela começar
+func (s *Star) publishSnapshot() {
+	s.mu.Lock()
+	s.publicado = StarSnapshot{State: s.State, IsVisible: s.IsVisible, Energy: s.Energy, PulseCount: s.PulseCount}
+	s.mu.Unlock()
+}
+
+// Última cópia publicada do estado da estrela
+func (s *Star) snapshot() StarSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.publicado
 }
 
 // Cria uma nova estrela
 func NewStar(x, y int, id string) *Star {
-	return &Star{
+	s := &Star{
 		X:         x,
 		Y:         y,
 		State:     StarVisible,
@@ -105,17 +137,25 @@ func NewStar(x, y int, id string) *Star {
 		IsVisible: true,
 		Energy:    0,
 		MapAccess: make(chan chan bool, 1),
+		Comandos:  make(chan StarCommand, 10),
+		rng:       rand.New(rand.NewSource(int64(y)<<32 | int64(x))),
 	}
+	s.publishSnapshot()
+	return s
 }
 
 func (s *Star) Run(ctx context.Context, gameEvents chan<- GameEvent, playerState <-chan PlayerState,
-	playerCollects <-chan PlayerCollect, starCommands <-chan StarCommand, mapMutex chan chan bool) {
+	playerCollects <-chan PlayerCollect, starCommands <-chan StarCommand, mapMutex chan chan bool, pausa <-chan bool) {
+
+	if s.Constelacao != nil {
+		defer constelacaoRemover(s.Constelacao, s.ID)
+	}
 
 	// Timers para diferentes comportamentos
-	visibilityTimer := time.NewTimer(StarVisibilityDuration)
-	pulseTimer := time.NewTimer(StarPulseDuration)
-	chargeTimer := time.NewTimer(StarChargeDuration)
-	timeoutTimer := time.NewTimer(StarTimeoutDuration)
+	visibilityTimer := time.NewTimer(duracaoJogo(StarVisibilityDuration))
+	pulseTimer := time.NewTimer(duracaoJogo(StarPulseDuration))
+	chargeTimer := time.NewTimer(duracaoJogo(StarChargeDuration))
+	timeoutTimer := time.NewTimer(duracaoJogo(StarTimeoutDuration))
 
 	defer visibilityTimer.Stop()
 	defer pulseTimer.Stop()
@@ -123,41 +163,58 @@ func (s *Star) Run(ctx context.Context, gameEvents chan<- GameEvent, playerState
 	defer timeoutTimer.Stop()
 
 	for {
+		s.publishSnapshot()
+
 		select {
 		case <-ctx.Done():
 			return
 
+		case pausado := <-pausa:
+			// Congela a estrela; ao retomar, os temporizadores recomeçam
+			if pausado {
+				if !aguardarRetomada(ctx, pausa) {
+					return
+				}
+				reiniciarTimer(visibilityTimer, duracaoJogo(s.getNextVisibilityDuration()))
+				reiniciarTimer(pulseTimer, duracaoJogo(StarPulseDuration))
+				reiniciarTimer(chargeTimer, duracaoJogo(StarChargeDuration))
+				reiniciarTimer(timeoutTimer, duracaoJogo(StarTimeoutDuration))
+			}
+
 		case playerPos := <-playerState:
 			s.LastPlayerPos = Position(playerPos)
 			s.handlePlayerMovement(gameEvents, playerPos)
 
 		case collect := <-playerCollects:
 			if collect.X == s.X && collect.Y == s.Y && s.IsVisible && s.State == StarVisible {
-				s.handleCollection(gameEvents, collect)
+				s.handleCollection(ctx, gameEvents, collect)
 				return // Estrela coletada, termina goroutine
 			}
 
 		case command := <-starCommands:
 			s.handleStarCommand(gameEvents, command)
 
+		case command := <-s.Comandos:
+			s.handleStarCommand(gameEvents, command)
+
 		case <-timeoutTimer.C:
 			s.handleTimeout(gameEvents)
-			timeoutTimer.Reset(StarTimeoutDuration)
+			timeoutTimer.Reset(duracaoJogo(StarTimeoutDuration))
 
 		case <-visibilityTimer.C:
 			s.toggleVisibility(gameEvents, mapMutex)
-			visibilityTimer.Reset(s.getNextVisibilityDuration())
+			visibilityTimer.Reset(duracaoJogo(s.getNextVisibilityDuration()))
 
 		case <-pulseTimer.C:
 			if s.State == StarPulsing {
 				s.handlePulse(gameEvents, mapMutex)
-				pulseTimer.Reset(StarPulseDuration)
+				pulseTimer.Reset(duracaoJogo(StarPulseDuration))
 			}
 
 		case <-chargeTimer.C:
 			if s.State == StarCharging {
 				s.handleChargeComplete(gameEvents)
-				chargeTimer.Reset(StarChargeDuration)
+				chargeTimer.Reset(duracaoJogo(StarChargeDuration))
 			}
 
 		// REQUISITO: Exclusão mútua usando canais
@@ -172,13 +229,15 @@ func (s *Star) handlePlayerMovement(gameEvents chan<- GameEvent, playerPos Playe
 
 	if distance <= 1 && s.State != StarPulsing {
 		s.changeState(StarPulsing, gameEvents)
+		// Avisa as vizinhas, que se carregam para quando o jogador chegar
+		s.broadcast("warning")
 	} else if distance > 3 && s.State == StarPulsing {
 		s.changeState(StarVisible, gameEvents)
 	}
 }
 
 // Manipula coleta da estrela
-func (s *Star) handleCollection(gameEvents chan<- GameEvent, collect PlayerCollect) {
+func (s *Star) handleCollection(ctx context.Context, gameEvents chan<- GameEvent, collect PlayerCollect) {
 	bonusType := "score"
 	value := 100
 
@@ -194,25 +253,39 @@ func (s *Star) handleCollection(gameEvents chan<- GameEvent, collect PlayerColle
 		value += s.Energy * 10
 	}
 
-	gameEvents <- GameEvent{
-		Type: EventStarCollected,
-		Data: StarCollectedData{
-			X:         s.X,
-			Y:         s.Y,
-			BonusType: bonusType,
-			Value:     value,
+	// A coleta não pode se perder: espera o jogo ler, ou o nível terminar
+	eventos := []GameEvent{
+		{
+			Type: EventStarCollected,
+			Data: StarCollectedData{
+				X:         s.X,
+				Y:         s.Y,
+				BonusType: bonusType,
+				Value:     value,
+			},
+		},
+		// Remover estrela do mapa
+		{
+			Type: EventRemoveElement,
+			Data: StarBonus{X: s.X, Y: s.Y},
 		},
 	}
-
-	// Remover estrela do mapa
-	gameEvents <- GameEvent{
-		Type: EventRemoveElement,
-		Data: StarBonus{X: s.X, Y: s.Y},
+	for _, ev := range eventos {
+		select {
+		case gameEvents <- ev:
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
 func (s *Star) handleStarCommand(gameEvents chan<- GameEvent, command StarCommand) {
 	switch command.Type {
+	case "player_moved":
+		if playerPos, ok := command.Data.(PlayerState); ok {
+			s.LastPlayerPos = Position(playerPos)
+			s.handlePlayerMovement(gameEvents, playerPos)
+		}
 	case "change_state":
 		if data, ok := command.Data.(StarState); ok {
 			s.changeState(data, gameEvents)
@@ -235,18 +308,19 @@ func (s *Star) handleStarCommand(gameEvents chan<- GameEvent, command StarComman
 func (s *Star) handleTimeout(gameEvents chan<- GameEvent) {
 	// Comportamento alternativo quando não recebe interação por tempo limite
 	actions := []string{"charge", "pulse", "hide", "energy_burst"}
-	action := actions[rand.Intn(len(actions))]
+	action := actions[s.rng.Intn(len(actions))]
 
 	switch action {
 	case "charge":
 		s.changeState(StarCharging, gameEvents)
 	case "pulse":
 		s.changeState(StarPulsing, gameEvents)
+		s.broadcast("sync_pulse")
 	case "hide":
 		s.changeState(StarInvisible, gameEvents)
 	case "energy_burst":
 		s.Energy += 50
-		gameEvents <- GameEvent{
+		enviarOuDescartar(gameEvents, GameEvent{
 			Type: EventStarCharged,
 			Data: StarChargedData{
 				X:        s.X,
@@ -254,18 +328,42 @@ func (s *Star) handleTimeout(gameEvents chan<- GameEvent) {
 				Energy:   s.Energy,
 				Duration: StarChargeDuration,
 			},
-		}
+		})
 	}
 
-	gameEvents <- GameEvent{
+	enviarOuDescartar(gameEvents, GameEvent{
 		Type: EventStarTimeout,
 		Data: StarTimeoutData{
-			X:       s.X,
-			Y:       s.Y,
-			Message: "Estrela mudou comportamento por timeout",
-			Action:  action,
-			StarID:  s.ID,
+			X:      s.X,
+			Y:      s.Y,
+			Action: action,
+			StarID: s.ID,
 		},
+	})
+
+	s.shareEnergy()
+}
+
+// Envia uma mensagem a todas as estrelas vizinhas
+func (s *Star) broadcast(message string) {
+	if s.Constelacao == nil {
+		return
+	}
+	constelacaoDifundir(s.Constelacao, s, StarCommunicationData{FromStarID: s.ID, Message: message})
+}
+
+// Passa metade da energia para a vizinha mais próxima
+func (s *Star) shareEnergy() {
+	if s.Constelacao == nil || s.Energy == 0 {
+		return
+	}
+	vizinha := constelacaoMaisProxima(s.Constelacao, s)
+	if vizinha == nil {
+		return
+	}
+	msg := StarCommunicationData{FromStarID: s.ID, ToStarID: vizinha.ID, Message: "share_energy", Data: s.Energy}
+	if constelacaoEnviar(s.Constelacao, msg) {
+		s.Energy -= s.Energy / 2
 	}
 }
 
@@ -294,7 +392,7 @@ func (s *Star) handlePulse(gameEvents chan<- GameEvent, mapMutex chan chan bool)
 	s.IsVisible = !s.IsVisible
 	s.PulseCount++
 
-	gameEvents <- GameEvent{
+	enviarOuDescartar(gameEvents, GameEvent{
 		Type: EventStarPulse,
 		Data: StarPulseData{
 			X:          s.X,
@@ -302,7 +400,7 @@ func (s *Star) handlePulse(gameEvents chan<- GameEvent, mapMutex chan chan bool)
 			IsVisible:  s.IsVisible,
 			PulseCount: s.PulseCount,
 		},
-	}
+	})
 
 	if s.PulseCount >= 10 {
 		s.PulseCount = 0
@@ -314,7 +412,7 @@ func (s *Star) handlePulse(gameEvents chan<- GameEvent, mapMutex chan chan bool)
 func (s *Star) handleChargeComplete(gameEvents chan<- GameEvent) {
 	s.Energy += 100
 
-	gameEvents <- GameEvent{
+	enviarOuDescartar(gameEvents, GameEvent{
 		Type: EventStarCharged,
 		Data: StarChargedData{
 			X:        s.X,
@@ -322,7 +420,7 @@ func (s *Star) handleChargeComplete(gameEvents chan<- GameEvent) {
 			Energy:   s.Energy,
 			Duration: StarChargeDuration,
 		},
-	}
+	})
 
 	s.changeState(StarVisible, gameEvents)
 }
@@ -340,10 +438,10 @@ func (s *Star) handleCommunication(gameEvents chan<- GameEvent, data StarCommuni
 		s.changeState(StarCharging, gameEvents)
 	}
 
-	gameEvents <- GameEvent{
+	enviarOuDescartar(gameEvents, GameEvent{
 		Type: EventStarCommunicate,
 		Data: data,
-	}
+	})
 }
 
 // Muda estado da estrela
@@ -361,7 +459,7 @@ func (s *Star) changeState(newState StarState, gameEvents chan<- GameEvent) {
 		s.IsVisible = true
 	}
 
-	gameEvents <- GameEvent{
+	enviarOuDescartar(gameEvents, GameEvent{
 		Type: EventStarStateChange,
 		Data: StarStateChangeData{
 			X:        s.X,
@@ -370,7 +468,7 @@ func (s *Star) changeState(newState StarState, gameEvents chan<- GameEvent) {
 			NewState: newState,
 			StarID:   s.ID,
 		},
-	}
+	})
 }
 
 // Exclusão mútua
@@ -385,6 +483,17 @@ func (s *Star) getNextVisibilityDuration() time.Duration {
 	return StarInvisibleDuration
 }
 
+// Para o timer, descarta um disparo pendente e o reinicia com a nova duração
+func reiniciarTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
+
 func abs(x int) int {
 	if x < 0 {
 		return -x
@@ -412,5 +521,5 @@ func (s *StarBonus) Run(ctx context.Context, out chan<- GameEvent, collected <-c
 		}
 	}()
 
-	star.Run(ctx, out, playerState, collected, starCommands, mapMutex)
+	star.Run(ctx, out, playerState, collected, starCommands, mapMutex, nil)
 }
@@ -0,0 +1,423 @@
+// exportar.go - Exportação do mapa e de capturas da partida para SVG e HTML
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Formatos de arquivo da exportação
+const (
+	FormatoSVG  = "svg"
+	FormatoHTML = "html"
+)
+
+// Tamanho de uma célula da tela no SVG, em pixels
+const (
+	LarguraCelulaSVG = 10
+	AlturaCelulaSVG  = 18
+)
+
+// Largura mínima da tela exportada, para o HUD caber em mapas estreitos
+const LarguraExportacaoMinima = 80
+
+// Marcas das sobreposições desenhadas sobre as células exportadas
+const (
+	MarcaCaminho      = "caminho"
+	MarcaAlcancavel   = "alcancavel"
+	MarcaInalcancavel = "inalcancavel"
+)
+
+// Cores das marcas, translúcidas para o tile continuar visível
+var coresMarcas = map[string]string{
+	MarcaCaminho:      "rgba(241,76,76,0.45)",
+	MarcaAlcancavel:   "rgba(35,209,139,0.45)",
+	MarcaInalcancavel: "rgba(214,112,214,0.55)",
+}
+
+// Ordem das marcas na legenda
+var ordemMarcas = []string{MarcaCaminho, MarcaAlcancavel, MarcaInalcancavel}
+
+// Cores do terminal na exportação, como em um terminal de fundo escuro
+var coresHex = map[Cor]string{
+	termbox.ColorBlack:        "#000000",
+	termbox.ColorRed:          "#cd3131",
+	termbox.ColorGreen:        "#0dbc79",
+	termbox.ColorYellow:       "#e5e510",
+	termbox.ColorBlue:         "#2472c8",
+	termbox.ColorMagenta:      "#bc3fbc",
+	termbox.ColorCyan:         "#11a8cd",
+	termbox.ColorWhite:        "#e5e5e5",
+	termbox.ColorDarkGray:     "#666666",
+	termbox.ColorLightRed:     "#f14c4c",
+	termbox.ColorLightGreen:   "#23d18b",
+	termbox.ColorLightYellow:  "#f5f543",
+	termbox.ColorLightBlue:    "#3b8eea",
+	termbox.ColorLightMagenta: "#d670d6",
+	termbox.ColorLightCyan:    "#29b8db",
+	termbox.ColorLightGray:    "#bbbbbb",
+}
+
+// Cores usadas no lugar de CorPadrao
+const (
+	FrentePadraoHex = "#cccccc"
+	FundoPadraoHex  = "#1e1e1e"
+)
+
+type OpcoesExportacao struct {
+	Formato  string
+	Titulo   string
+	Caminhos bool // caminho planejado do monstro
+	Alcance  bool // itens que o personagem alcança ou não da posição atual
+}
+
+// Aparência final de uma célula, já com os atributos do terminal resolvidos
+type estiloExportacao struct {
+	Frente, Fundo string
+	Negrito       bool
+	Sublinhado    bool
+	Esmaecido     bool
+}
+
+func exportarCorHex(cor Cor, padrao string) string {
+	if hex, ok := coresHex[cor&mascaraCor]; ok {
+		return hex
+	}
+	return padrao
+}
+
+func exportarEstilo(c Celula) estiloExportacao {
+	e := estiloExportacao{
+		Frente:     exportarCorHex(c.Cor, FrentePadraoHex),
+		Fundo:      exportarCorHex(c.CorFundo, FundoPadraoHex),
+		Negrito:    c.Cor&termbox.AttrBold != 0,
+		Sublinhado: c.Cor&termbox.AttrUnderline != 0,
+		Esmaecido:  c.Cor&termbox.AttrDim != 0,
+	}
+	if (c.Cor|c.CorFundo)&termbox.AttrReverse != 0 {
+		e.Frente, e.Fundo = e.Fundo, e.Frente
+	}
+	return e
+}
+
+// Desenha o jogo em uma tela em memória com o mapa inteiro e o HUD. As marcas
+// das sobreposições pedidas voltam em coordenadas da tela.
+func jogoRenderizarExportacao(jogo *Jogo, opcoes OpcoesExportacao) (*TelaMemoria, map[Position]string) {
+	largura, altura := jogoTamanhoMapa(jogo)
+	if largura < LarguraExportacaoMinima {
+		largura = LarguraExportacaoMinima
+	}
+	mem := NovaTelaMemoria(largura, altura+AlturaHUD)
+
+	// A câmera da partida não pode mudar por causa da captura
+	anterior, camera := tela, jogo.Camera
+	tela = mem
+	defer func() {
+		tela, jogo.Camera = anterior, camera
+	}()
+	interfaceDesenharJogo(jogo)
+
+	marcas := make(map[Position]string)
+	marcar := func(p Position, marca string) {
+		if x, y, ok := cameraParaTela(&jogo.Camera, p.X, p.Y); ok {
+			marcas[Position{X: x, Y: y}] = marca
+		}
+	}
+	if opcoes.Caminhos {
+		for _, p := range jogoCaminhoMonstro(jogo) {
+			marcar(p, MarcaCaminho)
+		}
+	}
+	if opcoes.Alcance {
+		alcancaveis, _ := caminhoBusca(jogo, Position{X: jogo.PosX, Y: jogo.PosY}, -1, 0)
+		for _, p := range jogoPosicoesItens(jogo) {
+			if _, ok := alcancaveis[p]; ok {
+				marcar(p, MarcaAlcancavel)
+			} else {
+				marcar(p, MarcaInalcancavel)
+			}
+		}
+	}
+	return mem, marcas
+}
+
+// Caminho que o monstro pretende seguir, o mesmo da sobreposição de depuração
+func jogoCaminhoMonstro(jogo *Jogo) []Position {
+	m := jogo.Monstro
+	if m == nil || jogoMonstroCapturado(jogo) {
+		return nil
+	}
+	s := m.snapshot()
+	if s.Estado == Fleeing {
+		return s.Fuga
+	}
+	// Com a rota do jogo o monstro a segue, atravessando portais; sem ela, dá o passo direto
+	if len(s.Rota) > 0 {
+		return s.Rota
+	}
+	return monsterPlanejarCaminho(m.current_position, s.Destino, s.Estado)
+}
+
+// Posições dos itens coletáveis do mapa e das estrelas visíveis
+func jogoPosicoesItens(jogo *Jogo) []Position {
+	var itens []Position
+	for y, linha := range jogo.Mapa {
+		for x := range linha {
+			if elem, _ := jogoElementoEm(jogo, x, y); tileTipo(elem).Coletar != nil {
+				itens = append(itens, Position{X: x, Y: y})
+			}
+		}
+	}
+	for _, star := range jogo.Stars {
+		if star.snapshot().IsVisible {
+			itens = append(itens, Position{X: star.X, Y: star.Y})
+		}
+	}
+	return itens
+}
+
+// Gera o arquivo no formato pedido a partir do estado atual do jogo
+func jogoExportar(jogo *Jogo, opcoes OpcoesExportacao) (string, error) {
+	mem, marcas := jogoRenderizarExportacao(jogo, opcoes)
+	switch opcoes.Formato {
+	case FormatoSVG:
+		return exportarSVG(mem, marcas, opcoes.Titulo), nil
+	case FormatoHTML:
+		return exportarHTML(mem, marcas, opcoes.Titulo), nil
+	}
+	return "", textosErro("exportar.formato_desconhecido", opcoes.Formato, FormatoSVG, FormatoHTML)
+}
+
+// Marcas presentes, na ordem da legenda
+func exportarMarcasUsadas(marcas map[Position]string) []string {
+	usadas := make(map[string]bool)
+	for _, m := range marcas {
+		usadas[m] = true
+	}
+	var lista []string
+	for _, m := range ordemMarcas {
+		if usadas[m] {
+			lista = append(lista, m)
+		}
+	}
+	return lista
+}
+
+// SVG com um retângulo de fundo e um texto por célula, para o alinhamento não
+// depender da largura da fonte
+func exportarSVG(mem *TelaMemoria, marcas map[Position]string, titulo string) string {
+	largura, altura := mem.Largura*LarguraCelulaSVG, mem.Altura*AlturaCelulaSVG
+	legenda := exportarMarcasUsadas(marcas)
+	alturaTotal := altura + len(legenda)*AlturaCelulaSVG
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", largura, alturaTotal, largura, alturaTotal)
+	fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(titulo))
+	fmt.Fprintf(&sb, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", FundoPadraoHex)
+	fmt.Fprintf(&sb, "<g font-family=\"DejaVu Sans Mono, Menlo, Consolas, monospace\" font-size=\"%d\" text-anchor=\"middle\">\n", AlturaCelulaSVG-4)
+	for y, linha := range mem.Celulas {
+		for x, c := range linha {
+			e := exportarEstilo(c)
+			px, py := x*LarguraCelulaSVG, y*AlturaCelulaSVG
+			if e.Fundo != FundoPadraoHex {
+				fmt.Fprintf(&sb, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", px, py, LarguraCelulaSVG, AlturaCelulaSVG, e.Fundo)
+			}
+			if m, ok := marcas[Position{X: x, Y: y}]; ok {
+				fmt.Fprintf(&sb, "<rect class=\"%s\" x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", m, px, py, LarguraCelulaSVG, AlturaCelulaSVG, coresMarcas[m])
+			}
+			if c.Ch == ' ' || c.Ch == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "<text x=\"%d\" y=\"%d\" fill=\"%s\"%s>%s</text>\n",
+				px+LarguraCelulaSVG/2, py+AlturaCelulaSVG-5, e.Frente, exportarAtributosSVG(e), html.EscapeString(string(c.Ch)))
+		}
+	}
+	sb.WriteString("</g>\n")
+
+	// Legenda das sobreposições abaixo da tela
+	for i, m := range legenda {
+		py := altura + i*AlturaCelulaSVG
+		fmt.Fprintf(&sb, "<rect x=\"0\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", py, LarguraCelulaSVG, AlturaCelulaSVG, coresMarcas[m])
+		fmt.Fprintf(&sb, "<text x=\"%d\" y=\"%d\" fill=\"%s\" font-family=\"sans-serif\" font-size=\"%d\">%s</text>\n",
+			2*LarguraCelulaSVG, py+AlturaCelulaSVG-5, FrentePadraoHex, AlturaCelulaSVG-6, html.EscapeString(traduzir("exportar.legenda."+m)))
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func exportarAtributosSVG(e estiloExportacao) string {
+	var attrs string
+	if e.Negrito {
+		attrs += ` font-weight="bold"`
+	}
+	if e.Sublinhado {
+		attrs += ` text-decoration="underline"`
+	}
+	if e.Esmaecido {
+		attrs += ` fill-opacity="0.6"`
+	}
+	return attrs
+}
+
+// HTML com a tela em um bloco <pre>; células vizinhas com a mesma aparência
+// ficam no mesmo <span>
+func exportarHTML(mem *TelaMemoria, marcas map[Position]string, titulo string) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n<style>\n", html.EscapeString(titulo))
+	fmt.Fprintf(&sb, "body { background: %s; color: %s; font-family: sans-serif; }\n", FundoPadraoHex, FrentePadraoHex)
+	sb.WriteString("pre { font-family: \"DejaVu Sans Mono\", Menlo, Consolas, monospace; line-height: 1.2; }\n")
+	for _, m := range ordemMarcas {
+		fmt.Fprintf(&sb, ".%s { background-image: linear-gradient(%s, %s); }\n", m, coresMarcas[m], coresMarcas[m])
+	}
+	sb.WriteString("</style>\n</head>\n<body>\n<pre>")
+
+	type trecho struct {
+		estilo estiloExportacao
+		marca  string
+	}
+	for y, linha := range mem.Celulas {
+		var atual trecho
+		var texto strings.Builder
+		fechar := func() {
+			if texto.Len() > 0 {
+				sb.WriteString(exportarSpanHTML(atual.estilo, atual.marca, texto.String()))
+				texto.Reset()
+			}
+		}
+		for x, c := range linha {
+			t := trecho{exportarEstilo(c), marcas[Position{X: x, Y: y}]}
+			if t != atual {
+				fechar()
+				atual = t
+			}
+			ch := c.Ch
+			if ch == 0 {
+				ch = ' '
+			}
+			texto.WriteRune(ch)
+		}
+		fechar()
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</pre>\n")
+
+	if legenda := exportarMarcasUsadas(marcas); len(legenda) > 0 {
+		sb.WriteString("<ul>\n")
+		for _, m := range legenda {
+			fmt.Fprintf(&sb, "<li><span class=\"%s\">&nbsp;&nbsp;</span> %s</li>\n", m, html.EscapeString(traduzir("exportar.legenda."+m)))
+		}
+		sb.WriteString("</ul>\n")
+	}
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+func exportarSpanHTML(e estiloExportacao, marca, texto string) string {
+	// O esmaecido só atinge o símbolo, com a cor de frente translúcida
+	frente := e.Frente
+	if e.Esmaecido {
+		frente += "99"
+	}
+	estilo := []string{"color:" + frente}
+	if e.Fundo != FundoPadraoHex {
+		estilo = append(estilo, "background-color:"+e.Fundo)
+	}
+	if e.Negrito {
+		estilo = append(estilo, "font-weight:bold")
+	}
+	if e.Sublinhado {
+		estilo = append(estilo, "text-decoration:underline")
+	}
+	classe := ""
+	if marca != "" {
+		classe = fmt.Sprintf(" class=\"%s\"", marca)
+	}
+	return fmt.Sprintf("<span%s style=\"%s\">%s</span>", classe, strings.Join(estilo, ";"), html.EscapeString(texto))
+}
+
+// Comando "jogo export <mapa>": exporta o mapa como ele fica ao começar o nível
+func exportarComando(args []string, semente int64) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formato := fs.String("formato", FormatoSVG, traduzir("exportar.opcao.formato"))
+	saida := fs.String("saida", "", traduzir("exportar.opcao.saida"))
+	caminhos := fs.Bool("caminhos", false, traduzir("exportar.opcao.caminhos"))
+	alcance := fs.Bool("alcance", false, traduzir("exportar.opcao.alcance"))
+
+	// O mapa pode vir antes ou depois das opções
+	var mapa string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		mapa, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if mapa == "" && fs.NArg() > 0 {
+		mapa = fs.Arg(0)
+	}
+	if mapa == "" {
+		return textosErro("exportar.uso")
+	}
+	if *formato != FormatoSVG && *formato != FormatoHTML {
+		return textosErro("exportar.formato_desconhecido", *formato, FormatoSVG, FormatoHTML)
+	}
+	if *saida == "" {
+		*saida = strings.TrimSuffix(mapa, filepath.Ext(mapa)) + "." + *formato
+	}
+
+	jogo := jogoNovo()
+	jogoDefinirSemente(&jogo, semente)
+	if err := jogoCarregarMapa(mapa, &jogo); err != nil {
+		return err
+	}
+	conteudo, err := jogoExportar(&jogo, OpcoesExportacao{
+		Formato:  *formato,
+		Titulo:   filepath.Base(mapa),
+		Caminhos: *caminhos,
+		Alcance:  *alcance,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*saida, []byte(conteudo), 0644); err != nil {
+		return err
+	}
+	fmt.Println(*saida)
+	return nil
+}
+
+// Salva uma captura da partida no formato e com as sobreposições escolhidos
+// nas opções, com o nome formado pelo mapa e pelo horário. Os elementos ficam
+// congelados enquanto o caminho do monstro é lido.
+func jogoCapturar(jogo *Jogo) {
+	retomar := jogoCongelar(jogo)
+	defer retomar()
+
+	titulo := "jogo"
+	if jogo.Nivel < len(jogo.Mapas) {
+		titulo = filepath.Base(jogo.Mapas[jogo.Nivel])
+	}
+	opcoes := OpcoesExportacao{
+		Formato:  jogo.Opcoes.CapturaFormato,
+		Titulo:   titulo,
+		Caminhos: jogo.Opcoes.CapturaCaminhos,
+		Alcance:  jogo.Opcoes.CapturaAlcance,
+	}
+	nome := fmt.Sprintf("captura-%s-%s.%s", strings.TrimSuffix(titulo, filepath.Ext(titulo)), time.Now().Format("20060102-150405"), opcoes.Formato)
+	conteudo, err := jogoExportar(jogo, opcoes)
+	if err == nil {
+		err = os.WriteFile(nome, []byte(conteudo), 0644)
+	}
+	if err != nil {
+		jogoMensagem(jogo, SeveridadeAviso, "captura.erro", err)
+		return
+	}
+	jogoMensagem(jogo, SeveridadeInfo, "captura.salva", nome)
+}
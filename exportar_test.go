@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Tela com símbolos que precisam de escape, um fundo colorido e duas marcas
+func exportarTelaTeste() (*TelaMemoria, map[Position]string) {
+	mem := NovaTelaMemoria(4, 2)
+	mem.DefinirCelula(0, 0, '<', termbox.ColorRed, CorPadrao)
+	mem.DefinirCelula(1, 0, '&', CorPadrao, CorPadrao)
+	mem.DefinirCelula(2, 0, '▤', termbox.ColorBlue, termbox.ColorYellow)
+	marcas := map[Position]string{
+		{X: 1, Y: 0}: MarcaCaminho,
+		{X: 3, Y: 1}: MarcaAlcancavel,
+	}
+	return mem, marcas
+}
+
+func TestExportarSVG(t *testing.T) {
+	mem, marcas := exportarTelaTeste()
+	svg := exportarSVG(mem, marcas, "a<b>&c")
+	esperados := []string{
+		`<title>a&lt;b&gt;&amp;c</title>`,
+		`<text x="5" y="13" fill="#cd3131">&lt;</text>`,
+		`<text x="15" y="13" fill="#cccccc">&amp;</text>`,
+		`<rect x="20" y="0" width="10" height="18" fill="#e5e510"/>`,
+		`<text x="25" y="13" fill="#2472c8">▤</text>`,
+		`<rect class="caminho" x="10" y="0" width="10" height="18" fill="` + coresMarcas[MarcaCaminho] + `"/>`,
+		`<rect class="alcancavel" x="30" y="18" width="10" height="18" fill="` + coresMarcas[MarcaAlcancavel] + `"/>`,
+		traduzir("exportar.legenda.caminho"),
+		traduzir("exportar.legenda.alcancavel"),
+	}
+	for _, e := range esperados {
+		if !strings.Contains(svg, e) {
+			t.Errorf("SVG sem %s:\n%s", e, svg)
+		}
+	}
+	if strings.Contains(svg, "<b>") || strings.Contains(svg, traduzir("exportar.legenda.inalcancavel")) {
+		t.Errorf("SVG com o título sem escape ou legenda de marca ausente:\n%s", svg)
+	}
+	// Duas marcas: a tela e duas linhas de legenda
+	if !strings.Contains(svg, `height="72"`) {
+		t.Errorf("SVG sem espaço para a legenda:\n%s", svg)
+	}
+}
+
+func TestExportarHTML(t *testing.T) {
+	mem, marcas := exportarTelaTeste()
+	pagina := exportarHTML(mem, marcas, "a<b>&c")
+	esperados := []string{
+		`<title>a&lt;b&gt;&amp;c</title>`,
+		`<span style="color:#cd3131">&lt;</span>`,
+		`<span class="caminho" style="color:#cccccc">&amp;</span>`,
+		`<span style="color:#2472c8;background-color:#e5e510">▤</span>`,
+		`<span class="alcancavel" style="color:#cccccc"> </span>`,
+		`<li><span class="caminho">&nbsp;&nbsp;</span> ` + traduzir("exportar.legenda.caminho") + `</li>`,
+		`<li><span class="alcancavel">&nbsp;&nbsp;</span> ` + traduzir("exportar.legenda.alcancavel") + `</li>`,
+	}
+	for _, e := range esperados {
+		if !strings.Contains(pagina, e) {
+			t.Errorf("HTML sem %s:\n%s", e, pagina)
+		}
+	}
+	if strings.Contains(pagina, "<b>") || strings.Contains(pagina, `<li><span class="inalcancavel">`) {
+		t.Errorf("HTML com o título sem escape ou legenda de marca ausente:\n%s", pagina)
+	}
+}
+
+// Uma estrela fica fechada atrás das paredes, fora do alcance do personagem
+const exportarMapaTeste = `▤▤▤▤▤▤▤▤▤▤
+▤☺ ★  ▤★ ▤
+▤     ▤▤▤▤
+▤  ☠     ▤
+▤▤▤▤▤▤▤▤▤▤
+`
+
+func TestExportarComando(t *testing.T) {
+	dir := t.TempDir()
+	mapa := filepath.Join(dir, "mapa&1.txt")
+	if err := os.WriteFile(mapa, []byte(exportarMapaTeste), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	saida := filepath.Join(dir, "saida.html")
+	if err := exportarComando([]string{mapa, "-formato", "html", "-saida", saida, "-alcance", "-caminhos"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	dados, err := os.ReadFile(saida)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pagina := string(dados)
+	for _, e := range []string{"<title>mapa&amp;1.txt</title>", `class="alcancavel"`, `class="inalcancavel"`, `class="caminho"`} {
+		if !strings.Contains(pagina, e) {
+			t.Errorf("exportação sem %s:\n%s", e, pagina)
+		}
+	}
+
+	// Sem -saida, o arquivo leva o nome do mapa; o formato padrão é SVG
+	if err := exportarComando([]string{"-alcance", mapa}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if dados, err := os.ReadFile(filepath.Join(dir, "mapa&1.svg")); err != nil || !strings.HasPrefix(string(dados), "<svg") {
+		t.Errorf("SVG padrão não gravado: %v", err)
+	}
+
+	casos := []struct {
+		nome string
+		args []string
+		id   string // identificador do erro; vazio para erros do pacote flag
+	}{
+		{"formato desconhecido", []string{mapa, "-formato", "pdf"}, "exportar.formato_desconhecido"},
+		{"sem o mapa", []string{"-formato", "svg"}, "exportar.uso"},
+		{"opção em inglês", []string{mapa, "-format", "html"}, ""},
+	}
+	for _, c := range casos {
+		err := exportarComando(c.args, 1)
+		if e, ok := err.(ErroTexto); err == nil || ok != (c.id != "") || (ok && e.ID != c.id) {
+			t.Errorf("%s: erro %v, esperado %q", c.nome, err, c.id)
+		}
+	}
+}
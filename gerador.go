@@ -0,0 +1,224 @@
+// gerador.go - Geradores que recriam estrelas e itens de invisibilidade no mapa
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Itens que um gerador pode criar
+const (
+	GeradorEstrela        = "estrela"
+	GeradorInvisibilidade = "invisibilidade"
+)
+
+// Evento do gerador pedindo ao jogo que crie um item
+const EventGerarItem = "SpawnItem"
+
+// Tentativas de sortear um tile livre para um gerador sem posições fixas
+const TentativasPosicaoGerador = 200
+
+// Intervalo entre os ticks do gerador; a recarga é contada nesses ticks, que
+// param enquanto o jogo está pausado
+const TickGerador = 100 * time.Millisecond
+
+// Gerador recria um item de tempos em tempos, enquanto houver menos itens
+// dele no mapa que o máximo. A recarga recomeça quando um item dele some do
+// mapa. Sem posições fixas, sorteia um tile livre.
+type Gerador struct {
+	Item     string        `json:"item"`
+	Recarga  time.Duration `json:"recarga"`
+	Maximo   int           `json:"maximo"`
+	Posicoes []Position    `json:"posicoes,omitempty"`
+	Ativos   []Position    `json:"ativos,omitempty"` // itens criados que ainda estão no mapa
+	Coletas  chan int      `json:"-"`                // avisa a goroutine da nova geração quando um item some
+	rng      *rand.Rand    // sorteio das posições, separado do gerador das ações do jogador
+	geracao  int           // aumenta a cada vez que o jogo percebe itens do gerador sumirem
+}
+
+type GerarItemData struct {
+	Indice  int // posição do gerador em Jogo.Geradores
+	Geracao int // geração do gerador conhecida pela goroutine ao pedir o item
+}
+
+// Lê uma linha "@gerador item recarga máximo [x,y ...]", com a recarga em segundos
+func geradorDeMetadado(campos []string) (*Gerador, error) {
+	if len(campos) < 4 {
+		return nil, textosErro("erro.gerador_formato")
+	}
+	g := &Gerador{Item: campos[1]}
+	if g.Item != GeradorEstrela && g.Item != GeradorInvisibilidade {
+		return nil, textosErro("erro.gerador_item", g.Item, GeradorEstrela, GeradorInvisibilidade)
+	}
+	segundos, err := strconv.ParseFloat(campos[2], 64)
+	if err != nil || segundos <= 0 {
+		return nil, textosErro("erro.gerador_recarga", campos[2])
+	}
+	g.Recarga = time.Duration(segundos * float64(time.Second))
+	if g.Maximo, err = strconv.Atoi(campos[3]); err != nil || g.Maximo <= 0 {
+		return nil, textosErro("erro.gerador_maximo", campos[3])
+	}
+	for _, campo := range campos[4:] {
+		var p Position
+		if _, err := fmt.Sscanf(strings.Replace(campo, ",", " ", 1), "%d %d", &p.X, &p.Y); err != nil {
+			return nil, textosErro("erro.gerador_posicao", campo)
+		}
+		g.Posicoes = append(g.Posicoes, p)
+	}
+	return g, nil
+}
+
+// Prepara o gerador para o nível em execução. A semente vem da semente do
+// nível, para os sorteios não mudarem os do jogador nem os de outro gerador.
+func geradorIniciar(g *Gerador, semente int64, indice int) {
+	g.Coletas = make(chan int, 1)
+	g.geracao = 0
+	g.rng = rand.New(rand.NewSource(semente + int64(indice) + 1))
+}
+
+// Pede um item ao jogo a cada recarga; quem decide se e onde ele aparece é o
+// jogo. Quando um item some, a recarga recomeça do zero. A recarga é contada
+// em ticks, e enquanto o jogo está pausado a goroutine não lê o ticker.
+func (g *Gerador) Run(ctx context.Context, indice int, out chan<- GameEvent, pausa <-chan bool) {
+	ticker := time.NewTicker(duracaoJogo(TickGerador))
+	defer ticker.Stop()
+
+	total := geradorTicksRecarga(g.Recarga)
+	restante := total
+	geracao := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case pausado := <-pausa:
+			if pausado && !aguardarRetomada(ctx, pausa) {
+				return
+			}
+		case geracao = <-g.Coletas:
+			restante = total
+		case <-ticker.C:
+			var pedir bool
+			if restante, pedir = geradorAvancarRecarga(restante, total); pedir {
+				enviarOuDescartar(out, GameEvent{Type: EventGerarItem, Data: GerarItemData{Indice: indice, Geracao: geracao}})
+			}
+		}
+	}
+}
+
+// Quantos ticks do gerador cabem na recarga, arredondando para cima
+func geradorTicksRecarga(recarga time.Duration) int {
+	ticks := int((recarga + TickGerador - 1) / TickGerador)
+	if ticks < 1 {
+		return 1
+	}
+	return ticks
+}
+
+// Conta um tick da recarga; ao terminar, pede um item e recomeça do total
+func geradorAvancarRecarga(restante, total int) (int, bool) {
+	restante--
+	if restante <= 0 {
+		return total, true
+	}
+	return restante, false
+}
+
+// Símbolo do tile do item criado pelo gerador
+func geradorSimbolo(g *Gerador) rune {
+	if g.Item == GeradorEstrela {
+		return StarElementVisible.simbolo
+	}
+	return InvisibilityItem.simbolo
+}
+
+// Esquece os itens do gerador que saíram do mapa. Se algum saiu, passa para
+// a próxima geração e avisa a goroutine para recomeçar a recarga; um aviso
+// ainda não lido é trocado pelo novo, então o envio nunca espera.
+func geradorAtualizarAtivos(jogo *Jogo, g *Gerador) {
+	var ativos []Position
+	for _, p := range g.Ativos {
+		if elem, ok := jogoElementoEm(jogo, p.X, p.Y); ok && elem.simbolo == geradorSimbolo(g) {
+			ativos = append(ativos, p)
+		}
+	}
+	if len(ativos) == len(g.Ativos) {
+		return
+	}
+	g.Ativos = ativos
+	g.geracao++
+	if g.Coletas == nil {
+		return
+	}
+	select {
+	case <-g.Coletas:
+	default:
+	}
+	g.Coletas <- g.geracao
+}
+
+// Confere os itens de todos os geradores depois das ações do jogador
+func jogoVerificarGeradores(jogo *Jogo) {
+	for _, g := range jogo.Geradores {
+		geradorAtualizarAtivos(jogo, g)
+	}
+}
+
+// Cria o item do gerador, se ele estiver abaixo do máximo e houver um tile
+// livre. Pedidos de uma geração anterior, feitos antes de um item do gerador
+// sumir, ficam sem efeito: a reposição espera a recarga recomeçada.
+func jogoGerarItem(jogo *Jogo, g *Gerador, geracao int) {
+	geradorAtualizarAtivos(jogo, g)
+	if geracao != g.geracao || len(g.Ativos) >= g.Maximo {
+		return
+	}
+
+	pos, ok := jogoPosicaoParaGerador(jogo, g)
+	if !ok {
+		return
+	}
+	switch g.Item {
+	case GeradorEstrela:
+		jogoCriarEstrela(jogo, pos.X, pos.Y)
+	case GeradorInvisibilidade:
+		jogoCriarInvisibilidade(jogo, pos.X, pos.Y)
+	}
+	g.Ativos = append(g.Ativos, pos)
+	jogoMensagem(jogo, SeveridadeDetalhe, "gerador.item", traduzir("gerador."+g.Item), pos.X, pos.Y)
+}
+
+// Escolhe entre as posições fixas livres ou, sem elas, sorteia um tile livre do mapa
+func jogoPosicaoParaGerador(jogo *Jogo, g *Gerador) (Position, bool) {
+	if len(g.Posicoes) > 0 {
+		var livres []Position
+		for _, p := range g.Posicoes {
+			if jogoTileLivre(jogo, p.X, p.Y) {
+				livres = append(livres, p)
+			}
+		}
+		if len(livres) == 0 {
+			return Position{}, false
+		}
+		return livres[g.rng.Intn(len(livres))], true
+	}
+	largura, altura := jogoTamanhoMapa(jogo)
+	for i := 0; i < TentativasPosicaoGerador && largura > 0 && altura > 0; i++ {
+		p := Position{X: g.rng.Intn(largura), Y: g.rng.Intn(altura)}
+		if jogoTileLivre(jogo, p.X, p.Y) {
+			return p, true
+		}
+	}
+	return Position{}, false
+}
+
+// Informa se o tile está vazio e sem o personagem ou o monstro
+func jogoTileLivre(jogo *Jogo, x, y int) bool {
+	elem, ok := jogoElementoEm(jogo, x, y)
+	if !ok || elem != Vazio || (x == jogo.PosX && y == jogo.PosY) {
+		return false
+	}
+	return jogo.Monstro == nil || jogo.Monstro.current_position != (Position{X: x, Y: y})
+}
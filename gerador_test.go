@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Nível vazio com um gerador pronto para criar itens, sem a goroutine dele
+func geradorJogoTeste(t *testing.T, g *Gerador) *Jogo {
+	t.Helper()
+	jogo := telaJogoTeste(12, 8, 1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	jogo.Contexto = ctx
+	jogo.Geradores = []*Gerador{g}
+	geradorIniciar(g, 42, 0)
+	return jogo
+}
+
+func TestGeradorTicksRecarga(t *testing.T) {
+	casos := []struct {
+		recarga time.Duration
+		ticks   int
+	}{
+		{0, 1},
+		{TickGerador / 2, 1},
+		{TickGerador, 1},
+		{TickGerador + 1, 2},
+		{2 * time.Second, 20},
+	}
+	for _, c := range casos {
+		if got := geradorTicksRecarga(c.recarga); got != c.ticks {
+			t.Errorf("geradorTicksRecarga(%v) = %d, esperado %d", c.recarga, got, c.ticks)
+		}
+	}
+}
+
+func TestGeradorAvancarRecarga(t *testing.T) {
+	restante, total := 3, 3
+	var pedidos []bool
+	for i := 0; i < 7; i++ {
+		var pedir bool
+		restante, pedir = geradorAvancarRecarga(restante, total)
+		pedidos = append(pedidos, pedir)
+	}
+	esperado := []bool{false, false, true, false, false, true, false}
+	for i := range esperado {
+		if pedidos[i] != esperado[i] {
+			t.Fatalf("pedidos = %v, esperado %v", pedidos, esperado)
+		}
+	}
+}
+
+// Pausado, o gerador não conta a recarga; ao retomar, continua de onde parou
+func TestGeradorPausaNaoContaRecarga(t *testing.T) {
+	g := &Gerador{Item: GeradorInvisibilidade, Recarga: 2 * TickGerador, Maximo: 1}
+	geradorIniciar(g, 1, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan GameEvent, 10)
+	pausa := make(chan bool, 1)
+	pausa <- true
+	go g.Run(ctx, 0, out, pausa)
+
+	select {
+	case ev := <-out:
+		t.Fatalf("pedido com o jogo pausado: %+v", ev)
+	case <-time.After(5 * TickGerador):
+	}
+	pausa <- false
+	select {
+	case ev := <-out:
+		if data, ok := ev.Data.(GerarItemData); !ok || data.Indice != 0 || data.Geracao != 0 {
+			t.Errorf("pedido inesperado %+v", ev)
+		}
+	case <-time.After(20 * TickGerador):
+		t.Fatal("nenhum pedido depois de retomar")
+	}
+}
+
+func TestGeradorMaximo(t *testing.T) {
+	g := &Gerador{Item: GeradorInvisibilidade, Recarga: time.Second, Maximo: 2}
+	jogo := geradorJogoTeste(t, g)
+	for i := 0; i < 5; i++ {
+		jogoGerarItem(jogo, g, g.geracao)
+	}
+	if len(g.Ativos) != 2 || len(jogo.InvisibilityItems) != 2 {
+		t.Fatalf("ativos = %v, itens = %d; esperado o máximo de 2", g.Ativos, len(jogo.InvisibilityItems))
+	}
+
+	// Um item some: a geração muda e o pedido da geração anterior fica sem efeito
+	sumiu := g.Ativos[0]
+	jogo.Mapa[sumiu.Y][sumiu.X] = Vazio
+	anterior := g.geracao
+	jogoVerificarGeradores(jogo)
+	if g.geracao != anterior+1 || len(g.Ativos) != 1 {
+		t.Fatalf("geração %d e ativos %v depois da coleta", g.geracao, g.Ativos)
+	}
+	if geracao := <-g.Coletas; geracao != g.geracao {
+		t.Errorf("goroutine avisada da geração %d, esperada %d", geracao, g.geracao)
+	}
+	jogoGerarItem(jogo, g, anterior)
+	if len(g.Ativos) != 1 {
+		t.Errorf("pedido de geração anterior criou um item: %v", g.Ativos)
+	}
+	jogoGerarItem(jogo, g, g.geracao)
+	if len(g.Ativos) != 2 {
+		t.Errorf("pedido da geração atual não criou o item: %v", g.Ativos)
+	}
+}
+
+func TestGeradorPosicoes(t *testing.T) {
+	t.Run("fixas", func(t *testing.T) {
+		fixas := []Position{{X: 3, Y: 2}, {X: 8, Y: 5}}
+		g := &Gerador{Item: GeradorInvisibilidade, Recarga: time.Second, Maximo: 3, Posicoes: fixas}
+		jogo := geradorJogoTeste(t, g)
+		for i := 0; i < 3; i++ {
+			jogoGerarItem(jogo, g, g.geracao)
+		}
+		if len(g.Ativos) != 2 {
+			t.Fatalf("ativos = %v, esperadas só as 2 posições fixas", g.Ativos)
+		}
+		for _, p := range fixas {
+			if jogo.Mapa[p.Y][p.X] != InvisibilityItem {
+				t.Errorf("sem item na posição fixa %v", p)
+			}
+		}
+	})
+	t.Run("fixa ocupada", func(t *testing.T) {
+		g := &Gerador{Item: GeradorEstrela, Recarga: time.Second, Maximo: 1, Posicoes: []Position{{X: 1, Y: 1}}}
+		jogo := geradorJogoTeste(t, g)
+		jogoGerarItem(jogo, g, g.geracao)
+		if len(g.Ativos) != 0 || len(jogo.Stars) != 0 {
+			t.Errorf("item criado sob o personagem: %v", g.Ativos)
+		}
+	})
+	t.Run("sorteada", func(t *testing.T) {
+		g := &Gerador{Item: GeradorEstrela, Recarga: time.Second, Maximo: 10}
+		jogo := geradorJogoTeste(t, g)
+		jogo.Mapa[4][4] = Parede
+		for i := 0; i < 10; i++ {
+			jogoGerarItem(jogo, g, g.geracao)
+		}
+		if len(g.Ativos) != 10 || len(jogo.Stars) != 10 {
+			t.Fatalf("ativos = %d, estrelas = %d; esperado 10", len(g.Ativos), len(jogo.Stars))
+		}
+		vistas := make(map[Position]bool)
+		for _, p := range g.Ativos {
+			if vistas[p] || p == (Position{X: 1, Y: 1}) || p == (Position{X: 4, Y: 4}) {
+				t.Errorf("posição sorteada inválida %v", p)
+			}
+			vistas[p] = true
+			if jogo.Mapa[p.Y][p.X] != StarElementVisible {
+				t.Errorf("sem estrela em %v", p)
+			}
+		}
+	})
+}
+
+// A coleta vai só para o item do tile coletado
+func TestColetaEntregueAoItem(t *testing.T) {
+	jogo := telaJogoTeste(12, 8, 1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jogo.Contexto = ctx
+	jogoCriarInvisibilidade(jogo, 3, 3)
+	jogoCriarInvisibilidade(jogo, 6, 3)
+
+	jogoEntregarColeta(jogo, PlayerCollect{X: 6, Y: 3})
+	select {
+	case ev := <-jogo.GameEvents:
+		if data, ok := ev.Data.(Invisibility); ev.Type != EventRemoveElement || !ok || data.X != 6 || data.Y != 3 {
+			t.Fatalf("evento inesperado %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("o item coletado não respondeu")
+	}
+	select {
+	case ev := <-jogo.GameEvents:
+		t.Fatalf("outro item respondeu à coleta: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	jogoEntregarColeta(jogo, PlayerCollect{X: 3, Y: 3})
+	select {
+	case ev := <-jogo.GameEvents:
+		if data, ok := ev.Data.(Invisibility); !ok || data.X != 3 {
+			t.Fatalf("evento inesperado %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("o primeiro item perdeu a coleta")
+	}
+}
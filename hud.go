@@ -0,0 +1,190 @@
+// hud.go - Painel de informações do jogador no rodapé da tela
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Espaço entre dois indicadores na mesma linha do HUD
+const EspacoIndicadores = 3
+
+// Linhas do HUD garantidas para as mensagens, mesmo com muitos indicadores
+const MinLinhasMensagensHUD = 2
+
+// Tamanho máximo da barra de invisibilidade, em caracteres
+const LarguraMaxBarraInvisibilidade = 20
+
+// Indicador é um pedaço de texto do HUD com sua cor
+type Indicador struct {
+	Texto string
+	Cor   Cor
+}
+
+// Desenha o HUD nas últimas AlturaHUD linhas da tela. Os indicadores são
+// distribuídos em quantas linhas a largura exigir; as linhas que sobram mostram
+// as últimas mensagens do registro, e a ajuda de teclas fica na última linha.
+func interfaceDesenharBarraDeStatus(jogo *Jogo, alturaTela int) {
+	largura, _ := tela.Tamanho()
+	topo := alturaTela - AlturaHUD
+
+	// Linha separando o mapa do HUD
+	interfaceDesenharTexto(0, topo, strings.Repeat("─", largura), CorTexto)
+
+	// Em terminais estreitos os rótulos são abreviados para sobrar espaço às mensagens
+	disponiveis := AlturaHUD - 2
+	linhas := hudDistribuir(hudIndicadores(jogo, largura, false), largura)
+	if len(linhas) > disponiveis-MinLinhasMensagensHUD {
+		linhas = hudDistribuir(hudIndicadores(jogo, largura, true), largura)
+	}
+	if len(linhas) > disponiveis {
+		linhas = linhas[:disponiveis]
+	}
+
+	// Últimas mensagens, a mais recente embaixo
+	msgs := registroFiltrar(jogo.Mensagens, jogo.Opcoes.MensagensDetalhadas)
+	if n := disponiveis - len(linhas); len(msgs) > n {
+		msgs = msgs[len(msgs)-n:]
+	}
+	for _, m := range msgs {
+		linhas = append(linhas, []Indicador{{Texto: mensagemFormatar(m), Cor: severidadeCor(m.Severidade)}})
+	}
+
+	for i, linha := range linhas {
+		x := 0
+		for _, ind := range linha {
+			interfaceDesenharTexto(x, topo+1+i, hudCortar(ind.Texto, largura-x), ind.Cor)
+			x += len([]rune(ind.Texto)) + EspacoIndicadores
+		}
+	}
+
+	ajuda := hudAjuda(jogo.Opcoes.Teclas, false)
+	if len([]rune(ajuda)) > largura {
+		ajuda = hudAjuda(jogo.Opcoes.Teclas, true)
+	}
+	interfaceDesenharTexto(0, alturaTela-1, hudCortar(ajuda, largura), CorTexto)
+}
+
+// Monta os indicadores do HUD na ordem em que devem aparecer; no modo
+// compacto os rótulos do catálogo são trocados por símbolos
+func hudIndicadores(jogo *Jogo, largura int, compacto bool) []Indicador {
+	formatos := [...]string{"hud.pontos", "hud.vidas", "hud.tempo", "hud.estrelas", "hud.pulos", "hud.invisivel", "hud.poder"}
+	for i := range formatos {
+		formatos[i] = traduzir(formatos[i])
+	}
+	if compacto {
+		formatos = [...]string{"%d", "%s", "%s", "★%d/%d", "⇈%d", "¤%s", "✦%s"}
+	}
+
+	inds := []Indicador{
+		{Texto: fmt.Sprintf(formatos[0], jogo.Partida.Pontos), Cor: CorAmarelo},
+		{Texto: fmt.Sprintf(formatos[1], strings.Repeat("♥", jogo.Partida.Vidas)), Cor: CorVermelho},
+		{Texto: fmt.Sprintf(formatos[2], hudFormatarTempo(partidaTempo(jogo.Partida))), Cor: CorTexto},
+		{Texto: fmt.Sprintf(formatos[3], jogo.EstrelasColetadas, jogo.EstrelasTotal), Cor: CorAmarelo},
+		{Texto: fmt.Sprintf(formatos[4], jogo.DoubleJumps), Cor: CorTexto},
+	}
+
+	if jogoMonstroCacando(jogo) {
+		inds = append(inds, Indicador{Texto: traduzir("hud.cacado"), Cor: CorVermelho})
+	} else {
+		inds = append(inds, Indicador{Texto: traduzir("hud.oculto"), Cor: CorVerde})
+	}
+
+	if jogo.InvisibleSteps > 0 {
+		inds = append(inds, Indicador{
+			Texto: fmt.Sprintf(formatos[5], hudBarra(jogo.InvisibleSteps, InvisibilityDuration, hudLarguraBarra(largura))),
+			Cor:   CorCinzaEscuro,
+		})
+	}
+
+	if jogo.PowerSteps > 0 {
+		inds = append(inds, Indicador{
+			Texto: fmt.Sprintf(formatos[6], hudBarra(jogo.PowerSteps, PowerDuration, hudLarguraBarra(largura))),
+			Cor:   CorAzul,
+		})
+	}
+
+	inds = append(inds, Indicador{Texto: inventarioDescricao(jogo.Inventario), Cor: CorAmarelo})
+	return inds
+}
+
+// Reparte os indicadores em linhas que caibam na largura da tela
+func hudDistribuir(inds []Indicador, largura int) [][]Indicador {
+	var linhas [][]Indicador
+	var atual []Indicador
+	ocupado := 0
+	for _, ind := range inds {
+		tamanho := len([]rune(ind.Texto))
+		if len(atual) > 0 && ocupado+EspacoIndicadores+tamanho > largura {
+			linhas = append(linhas, atual)
+			atual, ocupado = nil, 0
+		}
+		if len(atual) > 0 {
+			ocupado += EspacoIndicadores
+		}
+		atual = append(atual, ind)
+		ocupado += tamanho
+	}
+	if len(atual) > 0 {
+		linhas = append(linhas, atual)
+	}
+	return linhas
+}
+
+// Linha de ajuda montada com a primeira tecla de cada ação
+func hudAjuda(teclas MapaTeclas, compacto bool) string {
+	tecla := func(acao string) string { return teclasPrincipal(teclas, acao) }
+	mover := tecla(AcaoCima) + tecla(AcaoEsquerda) + tecla(AcaoBaixo) + tecla(AcaoDireita)
+	if compacto {
+		return strings.Join([]string{mover, tecla(AcaoInteragir), "1-9", tecla(AcaoSalvar), tecla(AcaoMensagens), tecla(AcaoPausar)}, " ")
+	}
+	return traduzir("hud.ajuda", mover, tecla(AcaoInteragir), tecla(AcaoSalvar), tecla(AcaoMensagens), tecla(AcaoPausar))
+}
+
+// Informa se algum monstro está caçando o personagem
+func jogoMonstroCacando(jogo *Jogo) bool {
+	for _, estado := range jogo.EstadoMonstros {
+		if estado == Hunting {
+			return true
+		}
+	}
+	return false
+}
+
+// Barra de contagem regressiva, como "[██████░░░░] 12"
+func hudBarra(valor, maximo, largura int) string {
+	if valor > maximo {
+		maximo = valor
+	}
+	cheios := 0
+	if maximo > 0 {
+		cheios = (valor*largura + maximo - 1) / maximo
+	}
+	return fmt.Sprintf("[%s%s] %d", strings.Repeat("█", cheios), strings.Repeat("░", largura-cheios), valor)
+}
+
+// A barra encolhe em terminais estreitos para caber em uma linha com o rótulo
+func hudLarguraBarra(largura int) int {
+	return limitar(largura/4, 5, LarguraMaxBarraInvisibilidade)
+}
+
+func hudFormatarTempo(d time.Duration) string {
+	s := int(d.Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
+
+// Corta o texto para não passar da largura disponível
+func hudCortar(texto string, largura int) string {
+	r := []rune(texto)
+	if largura <= 0 {
+		return ""
+	}
+	if len(r) > largura {
+		return string(r[:largura])
+	}
+	return texto
+}
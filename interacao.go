@@ -0,0 +1,219 @@
+// interacao.go - Portas, chaves e alavancas acionadas com a tecla E
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Eventos produzidos pelas interações do jogador
+const (
+	EventPortaAberta      = "PortaAberta"
+	EventAlavancaAcionada = "AlavancaAcionada"
+	EventChaveColetada    = "ChaveColetada"
+)
+
+// Identificador usado quando o mapa não declara um para o tile
+const IdentificadorPadrao = "padrao"
+
+// Linhas do arquivo de mapa iniciadas por este caractere são metadados
+const PrefixoMetadados = '@'
+
+// Interativo guarda o identificador declarado no mapa para um tile interativo.
+// Chaves abrem portas com o mesmo ID; alavancas acionam portões com o mesmo ID.
+type Interativo struct {
+	ID string
+}
+
+type PortaAbertaData struct {
+	X, Y int
+	ID   string
+}
+
+type AlavancaAcionadaData struct {
+	X, Y   int
+	ID     string
+	Ligada bool
+}
+
+type ChaveColetadaData struct {
+	ID string
+}
+
+// Retorna o identificador declarado para o tile, ou o padrão se não houver
+func jogoIdentificador(jogo *Jogo, x, y int) string {
+	if it, ok := jogo.Interativos[Position{X: x, Y: y}]; ok {
+		return it.ID
+	}
+	return IdentificadorPadrao
+}
+
+// Lê uma linha de metadados no formato "@ x y id", "@gerador item recarga
+// máximo [x,y ...]" para geradores ou "@portal id personagem" para um par de
+// portais que não leva monstros
+func jogoLerMetadado(jogo *Jogo, linha string) error {
+	campos := strings.Fields(linha[1:])
+	if len(campos) > 0 && campos[0] == "gerador" {
+		g, err := geradorDeMetadado(campos)
+		if err != nil {
+			return textosErro("erro.metadado", linha, err)
+		}
+		jogo.Geradores = append(jogo.Geradores, g)
+		return nil
+	}
+	if len(campos) > 0 && campos[0] == "portal" {
+		if len(campos) != 3 || campos[2] != "personagem" {
+			return textosErro("erro.metadado", linha, textosErro("erro.metadado_portal"))
+		}
+		jogo.SoPersonagem[campos[1]] = true
+		return nil
+	}
+	var x, y int
+	var id string
+	if _, err := fmt.Sscanf(linha[1:], "%d %d %s", &x, &y, &id); err != nil {
+		return textosErro("erro.metadado", linha, err)
+	}
+	jogo.Interativos[Position{X: x, Y: y}] = &Interativo{ID: id}
+	return nil
+}
+
+// Retorna o elemento do tile, considerando o que está sob o personagem
+func jogoElementoEm(jogo *Jogo, x, y int) (Elemento, bool) {
+	if x == jogo.PosX && y == jogo.PosY {
+		return jogo.UltimoVisitado, true
+	}
+	if y < 0 || y >= len(jogo.Mapa) || x < 0 || x >= len(jogo.Mapa[y]) {
+		return Vazio, false
+	}
+	return jogo.Mapa[y][x], true
+}
+
+// Substitui o elemento do tile, considerando o que está sob o personagem
+func jogoDefinirElemento(jogo *Jogo, x, y int, elem Elemento) {
+	if x == jogo.PosX && y == jogo.PosY {
+		jogo.UltimoVisitado = elem
+		return
+	}
+	if y >= 0 && y < len(jogo.Mapa) && x >= 0 && x < len(jogo.Mapa[y]) {
+		jogo.Mapa[y][x] = elem
+	}
+}
+
+// Envia um evento pelo canal de eventos; se estiver cheio, trata-o imediatamente.
+// Na reprodução, o evento tratado é o da gravação.
+func jogoEmitirEvento(jogo *Jogo, event GameEvent) {
+	if jogo.Reproducao != nil {
+		reproducaoTratarDireto(jogo, jogo.Reproducao)
+		return
+	}
+	select {
+	case jogo.GameEvents <- event:
+	default:
+		if jogo.Gravacao != nil {
+			gravacaoRegistrarEvento(jogo.Gravacao, event, true)
+		}
+		jogoTratarEvento(jogo, event)
+	}
+}
+
+// Interage com o tile adjacente na direção em que o personagem está virado
+func personagemInteragir(jogo *Jogo) {
+	x, y := jogo.PosX+jogo.DirX, jogo.PosY+jogo.DirY
+	alvo, ok := jogoElementoEm(jogo, x, y)
+	if !ok {
+		jogoMensagem(jogo, SeveridadeInfo, "interagir.nada")
+		return
+	}
+
+	if t := tileTipo(alvo); t.AoInteragir != nil {
+		t.AoInteragir(jogo, x, y)
+		return
+	}
+	jogoMensagem(jogo, SeveridadeInfo, "interagir.nada")
+}
+
+// Abre a porta trancada com uma chave do mesmo identificador
+func personagemAbrirPorta(jogo *Jogo, x, y int) {
+	id := jogoIdentificador(jogo, x, y)
+	if jogo.Chaves[id] == 0 {
+		jogoMensagem(jogo, SeveridadeAviso, "porta.trancada", id)
+		return
+	}
+	jogo.Chaves[id]--
+	jogoEmitirEvento(jogo, GameEvent{
+		Type: EventPortaAberta,
+		Data: PortaAbertaData{X: x, Y: y, ID: id},
+	})
+}
+
+// Liga ou desliga a alavanca
+func personagemAcionarAlavanca(jogo *Jogo, x, y int) {
+	alvo, _ := jogoElementoEm(jogo, x, y)
+	jogoEmitirEvento(jogo, GameEvent{
+		Type: EventAlavancaAcionada,
+		Data: AlavancaAcionadaData{X: x, Y: y, ID: jogoIdentificador(jogo, x, y), Ligada: alvo.simbolo == AlavancaDesligada.simbolo},
+	})
+}
+
+// Coleta a chave "sob" o jogador. A chave não vai para o inventário, então
+// sempre retorna false.
+func ConsumirItemChave(jogo *Jogo) bool {
+	jogo.UltimoVisitado = Vazio
+	jogoEmitirEvento(jogo, GameEvent{
+		Type: EventChaveColetada,
+		Data: ChaveColetadaData{ID: jogoIdentificador(jogo, jogo.PosX, jogo.PosY)},
+	})
+	return false
+}
+
+// Trata os eventos de interação recebidos pelo loop principal
+func jogoTratarEventoInteracao(jogo *Jogo, event GameEvent) {
+	switch data := event.Data.(type) {
+	case ChaveColetadaData:
+		jogo.Chaves[data.ID]++
+		jogoAdicionarPontos(jogo, PontosChave)
+		jogoMensagem(jogo, SeveridadeInfo, "chave.coletada", data.ID)
+	case PortaAbertaData:
+		jogoDefinirElemento(jogo, data.X, data.Y, PortaAberta)
+		jogoAdicionarPontos(jogo, PontosPorta)
+		jogoMensagem(jogo, SeveridadeInfo, "porta.aberta", data.ID)
+	case AlavancaAcionadaData:
+		if data.Ligada {
+			jogoDefinirElemento(jogo, data.X, data.Y, AlavancaLigada)
+		} else {
+			jogoDefinirElemento(jogo, data.X, data.Y, AlavancaDesligada)
+		}
+
+		// Alterna todos os portões ligados à alavanca
+		bloqueados := 0
+		for pos, it := range jogo.Interativos {
+			if it.ID != data.ID {
+				continue
+			}
+			elem, _ := jogoElementoEm(jogo, pos.X, pos.Y)
+			switch elem.simbolo {
+			case PortaoFechado.simbolo:
+				jogoDefinirElemento(jogo, pos.X, pos.Y, PortaoAberto)
+			case PortaoAberto.simbolo:
+				if jogoPosicaoOcupada(jogo, pos.X, pos.Y) {
+					bloqueados++
+					continue
+				}
+				jogoDefinirElemento(jogo, pos.X, pos.Y, PortaoFechado)
+			}
+		}
+		if bloqueados > 0 {
+			jogoMensagem(jogo, SeveridadeAviso, "alavanca.bloqueada", data.ID, bloqueados)
+		} else {
+			jogoMensagem(jogo, SeveridadeInfo, "alavanca.acionada", data.ID)
+		}
+	}
+}
+
+// Verifica se o personagem ou o monstro estão na posição
+func jogoPosicaoOcupada(jogo *Jogo, x, y int) bool {
+	if x == jogo.PosX && y == jogo.PosY {
+		return true
+	}
+	return jogo.Monstro != nil && jogo.Monstro.current_position == Position{X: x, Y: y}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Carrega um nível a partir do texto de um mapa, com os metadados
+func interacaoJogoTeste(t *testing.T, mapa string) *Jogo {
+	t.Helper()
+	nome := filepath.Join(t.TempDir(), "mapa.txt")
+	if err := os.WriteFile(nome, []byte(mapa), 0644); err != nil {
+		t.Fatal(err)
+	}
+	jogo := jogoNovo()
+	if err := jogoCarregarMapa(nome, &jogo); err != nil {
+		t.Fatal(err)
+	}
+	return &jogo
+}
+
+// Executa as ações em ordem, tratando os eventos de cada uma como o loop
+// principal faz: w/a/s/d movem, e interage, u usa o item do espaço 1 e r
+// reinicia o quebra-cabeça
+func interacaoJogar(jogo *Jogo, acoes string) {
+	for _, a := range acoes {
+		ev := EventoTeclado{Tipo: "mover", Tecla: a}
+		switch a {
+		case 'e':
+			ev = EventoTeclado{Tipo: "interagir"}
+		case 'u':
+			ev = EventoTeclado{Tipo: "usar_item", Tecla: '1'}
+		case 'r':
+			ev = EventoTeclado{Tipo: AcaoReiniciar}
+		}
+		personagemExecutarAcao(ev, jogo)
+		jogoProcessarEventos(jogo)
+	}
+}
+
+// Texto da última mensagem do registro
+func interacaoUltimaMensagem(jogo *Jogo) string {
+	if n := len(jogo.Mensagens.Mensagens); n > 0 {
+		return jogo.Mensagens.Mensagens[n-1].Texto
+	}
+	return ""
+}
+
+// Personagem, chave e porta em linha
+const interacaoMapaPorta = `▤▤▤▤▤▤
+▤☺⚷▣ ▤
+▤▤▤▤▤▤
+`
+
+// Duas alavancas sobre o personagem e o portão, para acionar uma delas de
+// cima do portão aberto
+const interacaoMapaAlavanca = `▤▤▤▤▤
+▤⌐⌐▤▤
+▤☺▦ ▤
+▤▤▤▤▤
+@ 1 1 v
+@ 2 1 v
+`
+
+func TestInteracaoPortasChavesAlavancas(t *testing.T) {
+	casos := []struct {
+		nome     string
+		mapa     string
+		acoes    string
+		tiles    map[Position]Elemento
+		chaves   map[string]int
+		mensagem []interface{} // identificador e parâmetros da última mensagem
+		pos      Position
+	}{
+		{
+			"chave abre a porta do mesmo identificador",
+			interacaoMapaPorta + "@ 2 1 azul\n@ 3 1 azul\n", "de",
+			map[Position]Elemento{{X: 3, Y: 1}: PortaAberta},
+			map[string]int{"azul": 0},
+			[]interface{}{"porta.aberta", "azul"}, Position{X: 2, Y: 1},
+		},
+		{
+			"chave de outro identificador não abre",
+			interacaoMapaPorta + "@ 2 1 vermelha\n@ 3 1 azul\n", "ded",
+			map[Position]Elemento{{X: 3, Y: 1}: PortaTrancada},
+			map[string]int{"vermelha": 1},
+			[]interface{}{"porta.trancada", "azul"}, Position{X: 2, Y: 1},
+		},
+		{
+			"sem metadados vale o identificador padrão",
+			interacaoMapaPorta, "dedd",
+			map[Position]Elemento{{X: 3, Y: 1}: PortaAberta},
+			map[string]int{IdentificadorPadrao: 0},
+			[]interface{}{"porta.aberta", IdentificadorPadrao}, Position{X: 4, Y: 1},
+		},
+		{
+			"cada chave abre uma porta",
+			"▤▤▤▤▤▤\n▤☺⚷▣▣▤\n▤▤▤▤▤▤\n@ 2 1 azul\n@ 3 1 azul\n@ 4 1 azul\n", "dede",
+			map[Position]Elemento{{X: 2, Y: 1}: Vazio, {X: 3, Y: 1}: PortaAberta, {X: 4, Y: 1}: PortaTrancada},
+			map[string]int{"azul": 0},
+			[]interface{}{"porta.trancada", "azul"}, Position{X: 3, Y: 1},
+		},
+		{
+			"alavanca abre o portão",
+			interacaoMapaAlavanca + "@ 2 2 v\n", "wed",
+			map[Position]Elemento{{X: 1, Y: 1}: AlavancaLigada, {X: 2, Y: 2}: PortaoAberto},
+			nil,
+			[]interface{}{"alavanca.acionada", "v"}, Position{X: 2, Y: 2},
+		},
+		{
+			"alavanca de novo fecha o portão",
+			interacaoMapaAlavanca + "@ 2 2 v\n", "weed",
+			map[Position]Elemento{{X: 1, Y: 1}: AlavancaDesligada, {X: 2, Y: 2}: PortaoFechado},
+			nil,
+			[]interface{}{"alavanca.acionada", "v"}, Position{X: 1, Y: 2},
+		},
+		{
+			"portão ocupado não fecha",
+			interacaoMapaAlavanca + "@ 2 2 v\n", "wedwe",
+			map[Position]Elemento{{X: 1, Y: 1}: AlavancaLigada, {X: 2, Y: 1}: AlavancaLigada, {X: 2, Y: 2}: PortaoAberto},
+			nil,
+			[]interface{}{"alavanca.bloqueada", "v", 1}, Position{X: 2, Y: 2},
+		},
+		{
+			"alavanca de outro identificador",
+			interacaoMapaAlavanca + "@ 2 2 x\n", "wed",
+			map[Position]Elemento{{X: 1, Y: 1}: AlavancaLigada, {X: 2, Y: 2}: PortaoFechado},
+			nil,
+			[]interface{}{"alavanca.acionada", "v"}, Position{X: 1, Y: 2},
+		},
+		{
+			"parede não interage",
+			interacaoMapaAlavanca, "se",
+			nil, nil,
+			[]interface{}{"interagir.nada"}, Position{X: 1, Y: 2},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			jogo := interacaoJogoTeste(t, c.mapa)
+			interacaoJogar(jogo, c.acoes)
+
+			for p, esperado := range c.tiles {
+				if elem, _ := jogoElementoEm(jogo, p.X, p.Y); elem != esperado {
+					t.Errorf("tile (%d, %d) = %q, esperado %q", p.X, p.Y, elem.simbolo, esperado.simbolo)
+				}
+			}
+			for id, n := range c.chaves {
+				if jogo.Chaves[id] != n {
+					t.Errorf("chaves %s = %d, esperado %d", id, jogo.Chaves[id], n)
+				}
+			}
+			if esperada := traduzir(c.mensagem[0].(string), c.mensagem[1:]...); interacaoUltimaMensagem(jogo) != esperada {
+				t.Errorf("última mensagem %q, esperada %q", interacaoUltimaMensagem(jogo), esperada)
+			}
+			if pos := (Position{X: jogo.PosX, Y: jogo.PosY}); pos != c.pos {
+				t.Errorf("personagem em %v, esperado em %v", pos, c.pos)
+			}
+		})
+	}
+}
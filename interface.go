@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/nsf/termbox-go"
 )
 
@@ -17,6 +21,7 @@ const (
 	CorFundoParede     = termbox.ColorDarkGray
 	CorTexto           = termbox.ColorDarkGray
 	CorAmarelo         = termbox.ColorYellow
+	CorAzul            = termbox.ColorBlue
 )
 
 // EventoTeclado representa uma ação detectada do teclado (como mover, sair ou interagir)
@@ -37,105 +42,670 @@ func interfaceFinalizar() {
 	termbox.Close()
 }
 
-// Lê um evento do teclado e o traduz para um EventoTeclado
-func interfaceLerEventoTeclado() EventoTeclado {
-	ev := termbox.PollEvent()
-	if ev.Type != termbox.EventKey {
-		return EventoTeclado{}
-	}
-	if ev.Key == termbox.KeyEsc {
-		return EventoTeclado{Tipo: "sair"}
-	}
-	if ev.Ch == 'e' {
-		return EventoTeclado{Tipo: "interagir"}
-	}
-	return EventoTeclado{Tipo: "mover", Tecla: ev.Ch}
+// Lê um evento do teclado e o traduz para um EventoTeclado conforme o mapa de teclas
+func interfaceLerEventoTeclado(teclas MapaTeclas) EventoTeclado {
+	return teclasTraduzir(termbox.PollEvent(), teclas)
 }
 
+// Número de linhas reservadas para a barra de status no rodapé da tela
+const AlturaHUD = 7
+
+// Tamanho mínimo do terminal para exibir ao menos uma linha do mapa e o HUD
+const (
+	LarguraMinimaTela = 20
+	AlturaMinimaTela  = AlturaHUD + 1
+)
+
 // Renderiza todo o estado atual do jogo na tela
 func interfaceDesenharJogo(jogo *Jogo) {
 	interfaceLimparTela()
 
-	// Desenha todos os elementos do mapa
-	for y, linha := range jogo.Mapa {
-		for x, elem := range linha {
-			interfaceDesenharElemento(x, y, elem)
+	// O layout é sempre recalculado a partir do tamanho atual do terminal
+	largura, altura := tela.Tamanho()
+	if largura < LarguraMinimaTela || altura < AlturaMinimaTela {
+		interfaceDesenharTelaPequena(largura, altura)
+		interfaceAtualizarTela()
+		return
+	}
+
+	// Ajusta a câmera à área da tela disponível acima do HUD
+	alturaMapa := altura - AlturaHUD
+	mapaLargura, mapaAltura := jogoTamanhoMapa(jogo)
+	cameraSeguir(&jogo.Camera, jogo.PosX, jogo.PosY, largura, alturaMapa, mapaLargura, mapaAltura)
+
+	// Com a neblina ativa, só o campo de visão é desenhado por completo
+	jogoAtualizarNeblina(jogo)
+
+	// Desenha apenas os elementos do mapa dentro da janela visível
+	cam := &jogo.Camera
+	for y := cam.Y; y < cam.Y+cam.Altura && y < len(jogo.Mapa); y++ {
+		linha := jogo.Mapa[y]
+		for x := cam.X; x < cam.X+cam.Largura && x < len(linha); x++ {
+			switch {
+			case jogoPosicaoVisivel(jogo, x, y):
+				interfaceDesenharNoMapa(jogo, x, y, linha[x])
+			case jogoPosicaoExplorada(jogo, x, y):
+				interfaceDesenharLembrado(jogo, x, y, linha[x])
+			}
 		}
 	}
 
+	// Ligações das mensagens recentes entre as estrelas
+	interfaceDesenharSinais(jogo)
+
 	// Desenha o personagem sobre o mapa
-	interfaceDesenharElemento(jogo.PosX, jogo.PosY, jogo.elementoJogador())
+	interfaceDesenharNoMapa(jogo, jogo.PosX, jogo.PosY, jogo.elementoJogador())
 
-	// Desenha o monstro se existir
-	if jogo.Monstro != nil {
-		interfaceDesenharElemento(jogo.Monstro.current_position.X, jogo.Monstro.current_position.Y, Inimigo)
+	// Desenha o monstro se existir, não estiver capturado e estiver no campo de visão
+	if m := jogo.Monstro; m != nil && !jogoMonstroCapturado(jogo) && jogoPosicaoVisivel(jogo, m.current_position.X, m.current_position.Y) {
+		interfaceDesenharNoMapa(jogo, m.current_position.X, m.current_position.Y, jogoElementoMonstro(jogo))
 	}
 
 	// Desenha as estrelas
 	for _, star := range jogo.Stars {
-		if star.IsVisible {
+		if star.snapshot().IsVisible && jogoPosicaoVisivel(jogo, star.X, star.Y) {
 			starElement := jogoGetStarElement(star)
-			interfaceDesenharElemento(star.X, star.Y, starElement)
+			interfaceDesenharNoMapa(jogo, star.X, star.Y, starElement)
 		}
 	}
 
-	interfaceDesenharBarraDeStatus(jogo)
+	if jogo.Depuracao {
+		interfaceDesenharDepuracao(jogo)
+	}
+
+	interfaceDesenharBarraDeStatus(jogo, altura)
 	interfaceAtualizarTela()
 }
 
 func interfaceLimparTela() {
-	termbox.Clear(CorPadrao, CorPadrao)
+	tela.Limpar()
 }
 
 func interfaceAtualizarTela() {
-	termbox.Flush()
+	tela.Atualizar()
 }
 
+// Desenha um elemento do mapa com a aparência definida pelo tema atual
 func interfaceDesenharElemento(x, y int, elem Elemento) {
-	termbox.SetCell(x, y, elem.simbolo, elem.cor, elem.corFundo)
+	a := temaAparencia(elem)
+	tela.DefinirCelula(x, y, a.Simbolo, a.Cor, a.CorFundo)
 }
 
-func interfaceDesenharBarraDeStatus(jogo *Jogo) {
+// Desenha uma célula avulsa, aplicando os símbolos e as cores do tema atual
+func interfaceDefinirCelula(x, y int, ch rune, cor, corFundo Cor) {
+	tela.DefinirCelula(x, y, temaSimbolo(ch), temaCor(cor), temaFundo(corFundo))
+}
 
-	for i, c := range jogo.StatusMsg {
-		termbox.SetCell(i, len(jogo.Mapa)+1, c, CorTexto, CorPadrao)
+// Escreve um texto na tela a partir da posição (x, y)
+func interfaceDesenharTexto(x, y int, texto string, cor Cor) {
+	for i, c := range []rune(texto) {
+		interfaceDefinirCelula(x+i, y, c, cor, CorPadrao)
 	}
+}
+
+// Aviso exibido quando o terminal não comporta o mapa e o HUD
+func interfaceDesenharTelaPequena(largura, altura int) {
+	msgs := []string{traduzir("tela.pequena"), traduzir("tela.minimo", LarguraMinimaTela, AlturaMinimaTela)}
+	for i, msg := range msgs {
+		linha := []rune(msg)
+		if len(linha) > largura {
+			linha = linha[:largura]
+		}
+		x := (largura - len(linha)) / 2
+		y := (altura-len(msgs))/2 + i
+		interfaceDesenharTexto(x, y, string(linha), CorVermelho)
+	}
+}
 
-	// Instruções fixas
-	msg := "Use WASD para mover e E para interagir. ESC para sair."
-	for i, c := range msg {
-		termbox.SetCell(i, len(jogo.Mapa)+3, c, CorTexto, CorPadrao)
+// Desenha um elemento dada sua posição no mapa, ignorando-o se estiver fora da câmera
+func interfaceDesenharNoMapa(jogo *Jogo, x, y int, elem Elemento) {
+	if tx, ty, visivel := cameraParaTela(&jogo.Camera, x, y); visivel {
+		interfaceDesenharElemento(tx, ty, elem)
 	}
 }
 
-// Versão assíncrona de leitura de eventos do teclado (não-bloqueante)
-func interfaceLerEventoTecladoAsync() <-chan EventoTeclado {
+// Desenha esmaecido um tile já visto e fora do campo de visão, sem as
+// entidades que estavam nele
+func interfaceDesenharLembrado(jogo *Jogo, x, y int, elem Elemento) {
+	tx, ty, visivel := cameraParaTela(&jogo.Camera, x, y)
+	if !visivel {
+		return
+	}
+	switch elem.simbolo {
+	case Personagem.simbolo, Inimigo.simbolo, StarElementVisible.simbolo, StarElementPulsing.simbolo, StarElementCharging.simbolo:
+		elem = Vazio
+	}
+	a := temaAparencia(elem)
+	tela.DefinirCelula(tx, ty, a.Simbolo, temaCor(CorCinzaEscuro)|termbox.AttrDim, CorPadrao)
+}
+
+// Versão assíncrona de leitura de eventos do teclado (não-bloqueante).
+// A tecla de pausa é entregue como "sair" e encerra a leitura.
+func interfaceLerEventoTecladoAsync(teclas MapaTeclas) <-chan EventoTeclado {
 	ch := make(chan EventoTeclado, 1)
 	go func() {
 		defer close(ch)
 		for {
-			switch ev := termbox.PollEvent(); ev.Type {
-			case termbox.EventKey:
-				evento := EventoTeclado{}
-				switch ev.Key {
-				case termbox.KeyEsc:
-					evento.Tipo = "sair"
-					ch <- evento
-					return
-				default:
-					switch ev.Ch {
-					case 'e', 'E':
-						evento.Tipo = "interagir"
-					case 'w', 'W', 'a', 'A', 's', 'S', 'd', 'D':
-						evento.Tipo = "mover"
-						evento.Tecla = ev.Ch
-					default:
-						continue // Ignorar outras teclas
-					}
-				}
-				ch <- evento
+			evento := teclasTraduzir(termbox.PollEvent(), teclas)
+			switch evento.Tipo {
+			case "":
+				continue // Ignorar outras teclas
+			case AcaoPausar:
+				ch <- EventoTeclado{Tipo: "sair"}
+				return
 			}
+			ch <- evento
 		}
 	}()
 	return ch
 }
+
+// Lê um evento do teclado no modo editor
+func interfaceLerEventoEditor() EventoTeclado {
+	ev := termbox.PollEvent()
+	if ev.Type == termbox.EventResize {
+		return EventoTeclado{Tipo: "redimensionar"}
+	}
+	if ev.Type != termbox.EventKey {
+		return EventoTeclado{}
+	}
+	switch ev.Key {
+	case termbox.KeyEsc:
+		return EventoTeclado{Tipo: "sair"}
+	case termbox.KeyCtrlQ:
+		return EventoTeclado{Tipo: "descartar"}
+	case termbox.KeyCtrlS:
+		return EventoTeclado{Tipo: "salvar"}
+	case termbox.KeyCtrlZ:
+		return EventoTeclado{Tipo: "desfazer"}
+	case termbox.KeyCtrlY:
+		return EventoTeclado{Tipo: "refazer"}
+	case termbox.KeyArrowUp:
+		return EventoTeclado{Tipo: "mover", Tecla: 'w'}
+	case termbox.KeyArrowLeft:
+		return EventoTeclado{Tipo: "mover", Tecla: 'a'}
+	case termbox.KeyArrowDown:
+		return EventoTeclado{Tipo: "mover", Tecla: 's'}
+	case termbox.KeyArrowRight:
+		return EventoTeclado{Tipo: "mover", Tecla: 'd'}
+	case termbox.KeySpace:
+		return EventoTeclado{Tipo: "ferramenta", Tecla: ' '}
+	case termbox.KeyTab:
+		return EventoTeclado{Tipo: "ferramenta", Tecla: '\t'}
+	}
+	return EventoTeclado{Tipo: "ferramenta", Tecla: ev.Ch}
+}
+
+// Número de linhas reservadas para o HUD do editor
+const AlturaHUDEditor = 6
+
+// Renderiza o mapa em edição, o cursor e o HUD do editor
+func interfaceDesenharEditor(ed *Editor) {
+	interfaceLimparTela()
+
+	largura, altura := tela.Tamanho()
+	if largura < LarguraMinimaTela || altura < AlturaHUDEditor+1 {
+		interfaceDesenharTelaPequena(largura, altura)
+		interfaceAtualizarTela()
+		return
+	}
+
+	mapaLargura := 0
+	for _, linha := range ed.Grade {
+		if len(linha) > mapaLargura {
+			mapaLargura = len(linha)
+		}
+	}
+	// A coluna extra permite posicionar o cursor além do fim da linha
+	cameraSeguir(&ed.Camera, ed.CursorX, ed.CursorY, largura, altura-AlturaHUDEditor, mapaLargura+1, len(ed.Grade))
+
+	cam := &ed.Camera
+	for y := cam.Y; y < cam.Y+cam.Altura && y < len(ed.Grade); y++ {
+		for x := cam.X; x < cam.X+cam.Largura && x < len(ed.Grade[y]); x++ {
+			interfaceDesenharElemento(x-cam.X, y-cam.Y, editorElemento(ed.Grade[y][x]))
+		}
+	}
+
+	// Destaca a área do retângulo em construção e o cursor
+	if ed.Ancora != nil {
+		x0, x1 := ordenar(ed.Ancora.X, ed.CursorX)
+		y0, y1 := ordenar(ed.Ancora.Y, ed.CursorY)
+		for y := y0; y <= y1; y++ {
+			for x := x0; x <= x1; x++ {
+				if tx, ty, ok := cameraParaTela(cam, x, y); ok {
+					simbolo, _ := editorSimbolo(ed, x, y)
+					if simbolo == 0 {
+						simbolo = ' '
+					}
+					interfaceDefinirCelula(tx, ty, simbolo, CorPadrao, CorCinzaEscuro)
+				}
+			}
+		}
+	}
+	if tx, ty, ok := cameraParaTela(cam, ed.CursorX, ed.CursorY); ok {
+		simbolo, _ := editorSimbolo(ed, ed.CursorX, ed.CursorY)
+		if simbolo == 0 {
+			simbolo = ' '
+		}
+		interfaceDefinirCelula(tx, ty, simbolo, CorPadrao|termbox.AttrReverse, CorPadrao)
+	}
+
+	interfaceDesenharHUDEditor(ed, largura, altura)
+	interfaceAtualizarTela()
+}
+
+// Desenha o rodapé do editor; os textos são cortados na largura da tela
+func interfaceDesenharHUDEditor(ed *Editor, larguraTela, alturaTela int) {
+	topo := alturaTela - AlturaHUDEditor
+
+	// Página atual da paleta, com o elemento selecionado em destaque. Um
+	// elemento que não cabe inteiro na largura da tela fica de fora.
+	x := 0
+	paleta := editorPaleta()
+	inicio := editorPagina(ed) * EditorPaginaPaleta
+	if paginas := editorPaginas(); paginas > 1 {
+		pagina := fmt.Sprintf("%d/%d ", editorPagina(ed)+1, paginas)
+		interfaceDesenharTexto(x, topo, hudCortar(pagina, larguraTela), CorTexto)
+		x += len(pagina)
+	}
+	for i := inicio; i < len(paleta) && i < inicio+EditorPaginaPaleta; i++ {
+		elem := paleta[i]
+		rotulo := fmt.Sprintf("%d:", i-inicio+1)
+		if x+len(rotulo)+1 > larguraTela {
+			break
+		}
+		interfaceDesenharTexto(x, topo, rotulo, CorTexto)
+		x += len(rotulo)
+		a := temaAparencia(elem)
+		if i == ed.Selecionado {
+			a.CorFundo = temaFundo(CorAmarelo)
+		}
+		if elem.simbolo == Vazio.simbolo {
+			a.Simbolo = temaSimbolo('·')
+		}
+		tela.DefinirCelula(x, topo, a.Simbolo, a.Cor, a.CorFundo)
+		x += 3
+	}
+	modificado := ""
+	if ed.Modificado {
+		modificado = "*"
+	}
+	info := fmt.Sprintf("%s%s (%d, %d) [%s]", ed.Arquivo, modificado, ed.CursorX, ed.CursorY, editorFerramentaNome(ed.Ferramenta))
+	interfaceDesenharTexto(x, topo, hudCortar(info, larguraTela-x), CorTexto)
+
+	interfaceDesenharTexto(0, topo+1, hudCortar(ed.StatusMsg, larguraTela), CorTexto)
+	for i, aviso := range ed.Avisos {
+		if i >= 2 {
+			break
+		}
+		interfaceDesenharTexto(0, topo+2+i, hudCortar("! "+aviso, larguraTela), CorVermelho)
+	}
+
+	interfaceDesenharTexto(0, topo+4, hudCortar(traduzir("editor.ajuda1"), larguraTela), CorTexto)
+	interfaceDesenharTexto(0, topo+5, hudCortar(traduzir("editor.ajuda2"), larguraTela), CorTexto)
+}
+
+// Desenha um texto centralizado horizontalmente na linha y
+func interfaceDesenharTextoCentralizado(y int, texto string, cor Cor) {
+	largura, _ := tela.Tamanho()
+	interfaceDesenharTexto((largura-len([]rune(texto)))/2, y, texto, cor)
+}
+
+// OpcaoMenu é uma linha de um menu. Uma opção pode abrir um submenu (empilhado),
+// ajustar um valor com as setas esquerda/direita ou encerrar a navegação com um resultado.
+type OpcaoMenu struct {
+	Rotulo    string
+	Valor     func() string   // valor exibido após o rótulo (opcional)
+	Ajustar   func(delta int) // chamado com -1/+1 pelas setas (opcional)
+	Submenu   func() *Menu    // menu empilhado ao escolher a opção (opcional)
+	Executar  func()          // tela aberta ao escolher a opção, voltando ao menu depois (opcional)
+	Resultado string          // retornado ao escolher a opção, se não houver submenu
+	Visivel   func() bool     // esconde a opção quando retorna false (opcional)
+}
+
+type Menu struct {
+	Titulo      string
+	Opcoes      []OpcaoMenu
+	Selecionada int
+	AoFechar    func() error // chamado quando o menu sai da pilha; o erro aparece no menu de baixo
+	Status      string       // aviso exibido até a próxima tecla
+}
+
+// Tira o menu do topo da pilha. Um erro ao fechá-lo fica no status do menu
+// que volta ao topo.
+func menuFechar(pilha []*Menu) []*Menu {
+	topo := pilha[len(pilha)-1]
+	pilha = pilha[:len(pilha)-1]
+	if topo.AoFechar != nil {
+		if err := topo.AoFechar(); err != nil && len(pilha) > 0 {
+			pilha[len(pilha)-1].Status = err.Error()
+		}
+	}
+	return pilha
+}
+
+// Navega por uma pilha de menus a partir da raiz. ESC desempilha o menu do topo;
+// na raiz, retorna "". Escolher uma opção com Resultado encerra a navegação.
+func interfaceNavegarMenus(raiz *Menu) string {
+	pilha := []*Menu{raiz}
+	fechar := func() { pilha = menuFechar(pilha) }
+
+	for {
+		menu := pilha[len(pilha)-1]
+		visiveis := menuOpcoesVisiveis(menu)
+		if menu.Selecionada >= len(visiveis) {
+			menu.Selecionada = 0
+		}
+		interfaceDesenharMenu(menu, visiveis)
+
+		ev := termbox.PollEvent()
+		if ev.Type == termbox.EventKey {
+			menu.Status = ""
+		}
+		if ev.Type != termbox.EventKey || len(visiveis) == 0 {
+			if ev.Type == termbox.EventKey && ev.Key == termbox.KeyEsc {
+				fechar()
+				if len(pilha) == 0 {
+					return ""
+				}
+			}
+			continue
+		}
+
+		opcao := visiveis[menu.Selecionada]
+		switch {
+		case ev.Key == termbox.KeyEsc:
+			fechar()
+			if len(pilha) == 0 {
+				return ""
+			}
+		case ev.Key == termbox.KeyArrowUp || ev.Ch == 'w':
+			menu.Selecionada = (menu.Selecionada + len(visiveis) - 1) % len(visiveis)
+		case ev.Key == termbox.KeyArrowDown || ev.Ch == 's':
+			menu.Selecionada = (menu.Selecionada + 1) % len(visiveis)
+		case (ev.Key == termbox.KeyArrowLeft || ev.Ch == 'a') && opcao.Ajustar != nil:
+			opcao.Ajustar(-1)
+		case (ev.Key == termbox.KeyArrowRight || ev.Ch == 'd') && opcao.Ajustar != nil:
+			opcao.Ajustar(1)
+		case ev.Key == termbox.KeyEnter || ev.Key == termbox.KeySpace:
+			switch {
+			case opcao.Submenu != nil:
+				pilha = append(pilha, opcao.Submenu())
+			case opcao.Executar != nil:
+				opcao.Executar()
+			case opcao.Resultado != "":
+				for len(pilha) > 0 {
+					fechar()
+				}
+				return opcao.Resultado
+			case opcao.Ajustar != nil:
+				opcao.Ajustar(1)
+			}
+		}
+	}
+}
+
+func menuOpcoesVisiveis(menu *Menu) []OpcaoMenu {
+	var visiveis []OpcaoMenu
+	for _, opcao := range menu.Opcoes {
+		if opcao.Visivel == nil || opcao.Visivel() {
+			visiveis = append(visiveis, opcao)
+		}
+	}
+	return visiveis
+}
+
+func interfaceDesenharMenu(menu *Menu, opcoes []OpcaoMenu) {
+	interfaceLimparTela()
+	_, altura := tela.Tamanho()
+	topo := (altura - len(opcoes) - 2) / 2
+	interfaceDesenharTextoCentralizado(topo, menu.Titulo, CorAmarelo)
+	for i, opcao := range opcoes {
+		texto := opcao.Rotulo
+		if opcao.Valor != nil {
+			texto += ": < " + opcao.Valor() + " >"
+		}
+		if i == menu.Selecionada {
+			texto = "> " + texto + " <"
+		} else {
+			texto = "  " + texto + "  "
+		}
+		interfaceDesenharTextoCentralizado(topo+2+i, texto, CorTexto)
+	}
+	if menu.Status != "" {
+		interfaceDesenharTextoCentralizado(altura-3, menu.Status, CorVermelho)
+	}
+	interfaceDesenharTextoCentralizado(altura-1, traduzir("menu.ajuda"), CorTexto)
+	interfaceAtualizarTela()
+}
+
+// Diálogo de confirmação com as opções Sim e Não
+func interfaceConfirmar(pergunta string) bool {
+	return interfaceNavegarMenus(&Menu{
+		Titulo: pergunta,
+		Opcoes: []OpcaoMenu{
+			{Rotulo: traduzir("menu.nao"), Resultado: "nao"},
+			{Rotulo: traduzir("menu.sim"), Resultado: "sim"},
+		},
+	}) == "sim"
+}
+
+// Pede um texto ao jogador (Enter confirma, ESC cancela retornando vazio)
+func interfaceLerTexto(titulo, pergunta string, maximo int) string {
+	var texto []rune
+	for {
+		interfaceLimparTela()
+		_, altura := tela.Tamanho()
+		meio := altura / 2
+		interfaceDesenharTextoCentralizado(meio-2, titulo, CorAmarelo)
+		interfaceDesenharTextoCentralizado(meio, pergunta, CorTexto)
+		interfaceDesenharTextoCentralizado(meio+2, "["+string(texto)+"_]", CorPadrao)
+		interfaceAtualizarTela()
+
+		ev := termbox.PollEvent()
+		if ev.Type != termbox.EventKey {
+			continue
+		}
+		switch {
+		case ev.Key == termbox.KeyEnter:
+			return string(texto)
+		case ev.Key == termbox.KeyEsc:
+			return ""
+		case ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+			if len(texto) > 0 {
+				texto = texto[:len(texto)-1]
+			}
+		case ev.Key == termbox.KeySpace && len(texto) < maximo:
+			texto = append(texto, ' ')
+		case ev.Ch != 0 && len(texto) < maximo:
+			texto = append(texto, ev.Ch)
+		}
+	}
+}
+
+// Tela de recordes; setas esquerda/direita alternam entre os mapas
+func interfaceMostrarRecordes(arq *ArquivoRecordes, chaveInicial string) error {
+	chaves := recordesChaves(arq)
+	atual := 0
+	for i, chave := range chaves {
+		if chave == chaveInicial {
+			atual = i
+		}
+	}
+
+	for {
+		interfaceLimparTela()
+		interfaceDesenharTextoCentralizado(1, traduzir("recordes.titulo"), CorAmarelo)
+		if len(chaves) == 0 {
+			interfaceDesenharTextoCentralizado(3, traduzir("recordes.vazio"), CorTexto)
+		} else {
+			tabela := arq.Tabelas[chaves[atual]]
+			interfaceDesenharTextoCentralizado(2, fmt.Sprintf("%v (%d/%d)", tabela.Mapas, atual+1, len(chaves)), CorTexto)
+			interfaceDesenharTexto(2, 4, fmt.Sprintf("%-3s %-16s %8s %5s %8s %s", "#", traduzir("recordes.nome"), traduzir("recordes.pontos"), traduzir("recordes.nivel"), traduzir("recordes.tempo"), traduzir("recordes.semente")), CorAmarelo)
+			for i, r := range tabela.Recordes {
+				linha := fmt.Sprintf("%-3d %-16s %8d %5d %8s %d", i+1, r.Nome, r.Pontos, r.Nivel,
+					(time.Duration(r.TempoS) * time.Second).String(), r.Semente)
+				interfaceDesenharTexto(2, 5+i, linha, CorTexto)
+			}
+		}
+		_, altura := tela.Tamanho()
+		interfaceDesenharTextoCentralizado(altura-1, traduzir("recordes.ajuda"), CorTexto)
+		interfaceAtualizarTela()
+
+		ev := termbox.PollEvent()
+		if ev.Type != termbox.EventKey {
+			continue
+		}
+		switch {
+		case ev.Key == termbox.KeyEsc || ev.Key == termbox.KeyEnter:
+			return nil
+		case ev.Key == termbox.KeyArrowLeft && len(chaves) > 0:
+			atual = (atual + len(chaves) - 1) % len(chaves)
+		case ev.Key == termbox.KeyArrowRight && len(chaves) > 0:
+			atual = (atual + 1) % len(chaves)
+		}
+	}
+}
+
+// Mostra o registro completo de mensagens em tela cheia. As setas rolam,
+// F alterna a exibição das mensagens de detalhe e ESC/L/Enter volta ao jogo.
+func interfaceMostrarMensagens(jogo *Jogo) {
+	detalhes := jogo.Opcoes.MensagensDetalhadas
+	rolagem := 0 // linhas roladas a partir da mensagem mais recente
+	for {
+		msgs := registroFiltrar(jogo.Mensagens, detalhes)
+		largura, altura := tela.Tamanho()
+		visiveis := altura - 4
+		if visiveis < 1 {
+			visiveis = 1
+		}
+		maxRolagem := len(msgs) - visiveis
+		if maxRolagem < 0 {
+			maxRolagem = 0
+		}
+		rolagem = limitar(rolagem, 0, maxRolagem)
+
+		interfaceLimparTela()
+		filtro := traduzir("mensagens.sem_detalhes")
+		if detalhes {
+			filtro = traduzir("mensagens.com_detalhes")
+		}
+		interfaceDesenharTextoCentralizado(0, traduzir("mensagens.titulo", len(msgs), filtro), CorAmarelo)
+		fim := len(msgs) - rolagem
+		inicio := fim - visiveis
+		if inicio < 0 {
+			inicio = 0
+		}
+		for i, m := range msgs[inicio:fim] {
+			texto := fmt.Sprintf("%-7s %s", severidadeNome(m.Severidade), mensagemFormatar(m))
+			interfaceDesenharTexto(1, 2+i, hudCortar(texto, largura-1), severidadeCor(m.Severidade))
+		}
+		interfaceDesenharTextoCentralizado(altura-1, traduzir("mensagens.ajuda"), CorTexto)
+		interfaceAtualizarTela()
+
+		ev := termbox.PollEvent()
+		if ev.Type != termbox.EventKey {
+			continue
+		}
+		switch {
+		case ev.Key == termbox.KeyEsc || ev.Key == termbox.KeyEnter || ev.Ch == 'l' || ev.Ch == 'L':
+			return
+		case ev.Key == termbox.KeyArrowUp || ev.Ch == 'w':
+			rolagem++
+		case ev.Key == termbox.KeyArrowDown || ev.Ch == 's':
+			rolagem--
+		case ev.Key == termbox.KeyPgup:
+			rolagem += visiveis
+		case ev.Key == termbox.KeyPgdn:
+			rolagem -= visiveis
+		case ev.Key == termbox.KeyHome:
+			rolagem = len(msgs)
+		case ev.Key == termbox.KeyEnd:
+			rolagem = 0
+		case ev.Ch == 'f' || ev.Ch == 'F':
+			detalhes = !detalhes
+		}
+	}
+}
+
+// Tela de redefinição de teclas. Enter troca as teclas da ação pela próxima
+// pressionada, A acrescenta uma tecla, Delete/Backspace limpa e R restaura o padrão.
+// Uma tecla já usada por outra ação é retirada dela, e o aviso aparece no rodapé.
+func interfaceRedefinirTeclas(m MapaTeclas) {
+	acoes := teclasAcoes()
+	selecionada := 0
+	aviso := ""
+	for {
+		_, altura := tela.Tamanho()
+		conflitos := teclasConflitos(m)
+
+		interfaceLimparTela()
+		interfaceDesenharTextoCentralizado(0, traduzir("teclas.titulo"), CorAmarelo)
+		for i, acao := range acoes {
+			cor := CorTexto
+			if i == selecionada {
+				cor = CorAmarelo
+			}
+			var rotulos []string
+			for _, tecla := range m[acao] {
+				rotulos = append(rotulos, teclaRotulo(tecla))
+				if _, ok := conflitos[teclaFisica(tecla)]; ok {
+					cor = CorVermelho
+				}
+			}
+			marcador := "  "
+			if i == selecionada {
+				marcador = "> "
+			}
+			interfaceDesenharTexto(2, 2+i, fmt.Sprintf("%s%-20s %s", marcador, acaoRotulo(acao), strings.Join(rotulos, ", ")), cor)
+		}
+		interfaceDesenharTexto(2, altura-2, aviso, CorAmarelo)
+		interfaceDesenharTextoCentralizado(altura-1, traduzir("teclas.ajuda"), CorTexto)
+		interfaceAtualizarTela()
+
+		ev := termbox.PollEvent()
+		if ev.Type != termbox.EventKey {
+			continue
+		}
+		acao := acoes[selecionada]
+		aviso = ""
+		switch {
+		case ev.Key == termbox.KeyEsc:
+			return
+		case ev.Key == termbox.KeyArrowUp || ev.Ch == 'w':
+			selecionada = (selecionada + len(acoes) - 1) % len(acoes)
+		case ev.Key == termbox.KeyArrowDown || ev.Ch == 's':
+			selecionada = (selecionada + 1) % len(acoes)
+		case ev.Key == termbox.KeyDelete || ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+			if acao == AcaoPausar {
+				aviso = traduzir("teclas.pausa_obrigatoria")
+			} else {
+				m[acao] = nil
+			}
+		case ev.Ch == 'r' || ev.Ch == 'R':
+			for a, teclas := range teclasPadrao() {
+				m[a] = teclas
+			}
+			aviso = traduzir("teclas.restauradas")
+		case ev.Key == termbox.KeyEnter || ev.Ch == 'a' || ev.Ch == 'A':
+			substituir := ev.Key == termbox.KeyEnter
+			interfaceDesenharTexto(2, altura-2, traduzir("teclas.pressione", acaoRotulo(acao)), CorAmarelo)
+			interfaceAtualizarTela()
+			nova := termbox.PollEvent()
+			for nova.Type != termbox.EventKey {
+				nova = termbox.PollEvent()
+			}
+			tecla := teclaNome(nova)
+			if nova.Key == termbox.KeyEsc || tecla == "" {
+				continue
+			}
+			if dona := teclasIndice(m)[tecla]; dona == AcaoPausar && acao != AcaoPausar && len(m[AcaoPausar]) == 1 {
+				aviso = traduzir("teclas.pausa_obrigatoria")
+				continue
+			}
+			if anterior := teclasAssociar(m, acao, tecla, substituir); anterior != "" {
+				aviso = traduzir("teclas.retirada", teclaRotulo(tecla), acaoRotulo(anterior))
+			}
+		}
+	}
+}
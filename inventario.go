@@ -0,0 +1,131 @@
+// inventario.go - Inventário de itens coletados pelo personagem
+package main
+
+import "fmt"
+
+// Tipos de itens que podem ser guardados
+const (
+	ItemInvisibilidade = "invisibilidade"
+	ItemPuloDuplo      = "pulo duplo"
+)
+
+// Limites padrão do inventário
+const (
+	InventarioCapacidadePadrao = 4 // número de espaços
+	InventarioMaxPilhaPadrao   = 5 // itens por espaço
+	PulosDuplosPorEstrela      = 3
+)
+
+// Pilha de itens do mesmo tipo ocupando um espaço do inventário
+type PilhaItem struct {
+	Tipo       string
+	Quantidade int
+}
+
+type Inventario struct {
+	Pilhas     []PilhaItem
+	Capacidade int
+	MaxPilha   int
+}
+
+func inventarioNovo() *Inventario {
+	return &Inventario{
+		Capacidade: InventarioCapacidadePadrao,
+		MaxPilha:   InventarioMaxPilhaPadrao,
+	}
+}
+
+// Adiciona um item; retorna false se não houver espaço
+func inventarioAdicionar(inv *Inventario, tipo string) bool {
+	for i := range inv.Pilhas {
+		if inv.Pilhas[i].Tipo == tipo && inv.Pilhas[i].Quantidade < inv.MaxPilha {
+			inv.Pilhas[i].Quantidade++
+			return true
+		}
+	}
+	if len(inv.Pilhas) >= inv.Capacidade {
+		return false
+	}
+	inv.Pilhas = append(inv.Pilhas, PilhaItem{Tipo: tipo, Quantidade: 1})
+	return true
+}
+
+// Verifica se ainda cabe um item do tipo
+func inventarioCabe(inv *Inventario, tipo string) bool {
+	for _, p := range inv.Pilhas {
+		if p.Tipo == tipo && p.Quantidade < inv.MaxPilha {
+			return true
+		}
+	}
+	return len(inv.Pilhas) < inv.Capacidade
+}
+
+// Remove um item do espaço indicado (começando em 0) e retorna seu tipo
+func inventarioRetirar(inv *Inventario, espaco int) (string, bool) {
+	if espaco < 0 || espaco >= len(inv.Pilhas) {
+		return "", false
+	}
+	tipo := inv.Pilhas[espaco].Tipo
+	inv.Pilhas[espaco].Quantidade--
+	if inv.Pilhas[espaco].Quantidade == 0 {
+		inv.Pilhas = append(inv.Pilhas[:espaco], inv.Pilhas[espaco+1:]...)
+	}
+	return tipo, true
+}
+
+// Guarda um item coletado no mapa, avisando se o inventário estiver cheio
+func personagemGuardarItem(jogo *Jogo, tipo string) bool {
+	if !inventarioAdicionar(jogo.Inventario, tipo) {
+		jogoMensagem(jogo, SeveridadeAviso, "inventario.cheio")
+		return false
+	}
+	jogoAdicionarPontos(jogo, pontosDoItem(tipo))
+	jogoMensagem(jogo, SeveridadeInfo, "inventario.guardado", itemNome(tipo))
+	return true
+}
+
+// Ativa o item do espaço indicado pela tecla (1 = primeiro espaço)
+func personagemUsarItem(tecla rune, jogo *Jogo) {
+	tipo, ok := inventarioRetirar(jogo.Inventario, int(tecla-'1'))
+	if !ok {
+		jogoMensagem(jogo, SeveridadeAviso, "inventario.vazio")
+		return
+	}
+
+	switch tipo {
+	case ItemInvisibilidade:
+		jogoEmitirEvento(jogo, GameEvent{
+			Type: EventApplyInvisibility,
+			Data: InvisibilityApplied{Duration: InvisibilityDuration},
+		})
+	case ItemPuloDuplo:
+		jogoEmitirEvento(jogo, GameEvent{
+			Type: "ApplyDoubleJump",
+			Data: DoubleJumpApplied{Jumps: PulosDuplosPorEstrela},
+		})
+	}
+}
+
+// Nome de um tipo de item no idioma atual
+func itemNome(tipo string) string {
+	switch tipo {
+	case ItemInvisibilidade:
+		return traduzir("item.invisibilidade")
+	case ItemPuloDuplo:
+		return traduzir("item.pulo_duplo")
+	}
+	return tipo
+}
+
+// Texto da linha de inventário exibida no HUD
+func inventarioDescricao(inv *Inventario) string {
+	desc := traduzir("inventario.titulo")
+	for i := 0; i < inv.Capacidade; i++ {
+		if i < len(inv.Pilhas) {
+			desc += fmt.Sprintf(" [%d] %s x%d", i+1, itemNome(inv.Pilhas[i].Tipo), inv.Pilhas[i].Quantidade)
+		} else {
+			desc += fmt.Sprintf(" [%d] -", i+1)
+		}
+	}
+	return desc
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInventarioPilhas(t *testing.T) {
+	inv := &Inventario{Capacidade: 2, MaxPilha: 2}
+	passos := []struct {
+		tipo     string
+		retirar  int // espaço retirado antes de adicionar; -1 para só adicionar
+		cabe     bool
+		esperado []PilhaItem
+	}{
+		{ItemInvisibilidade, -1, true, []PilhaItem{{ItemInvisibilidade, 1}}},
+		{ItemInvisibilidade, -1, true, []PilhaItem{{ItemInvisibilidade, 2}}},
+		// Pilha cheia: o terceiro abre outro espaço
+		{ItemInvisibilidade, -1, true, []PilhaItem{{ItemInvisibilidade, 2}, {ItemInvisibilidade, 1}}},
+		// Sem espaço livre, só cabe onde a pilha do tipo não está cheia
+		{ItemPuloDuplo, -1, false, []PilhaItem{{ItemInvisibilidade, 2}, {ItemInvisibilidade, 1}}},
+		{ItemInvisibilidade, -1, true, []PilhaItem{{ItemInvisibilidade, 2}, {ItemInvisibilidade, 2}}},
+		// Retirar o último item de um espaço libera o espaço
+		{ItemPuloDuplo, 1, false, []PilhaItem{{ItemInvisibilidade, 2}, {ItemInvisibilidade, 1}}},
+		{ItemPuloDuplo, 1, true, []PilhaItem{{ItemInvisibilidade, 2}, {ItemPuloDuplo, 1}}},
+		{ItemPuloDuplo, -1, true, []PilhaItem{{ItemInvisibilidade, 2}, {ItemPuloDuplo, 2}}},
+	}
+	for i, p := range passos {
+		if p.retirar >= 0 {
+			if _, ok := inventarioRetirar(inv, p.retirar); !ok {
+				t.Fatalf("passo %d: espaço %d vazio", i, p.retirar)
+			}
+		}
+		if cabe := inventarioCabe(inv, p.tipo); cabe != p.cabe {
+			t.Errorf("passo %d: inventarioCabe(%s) = %v", i, p.tipo, cabe)
+		}
+		if p.cabe {
+			if !inventarioAdicionar(inv, p.tipo) {
+				t.Errorf("passo %d: %s não foi adicionado", i, p.tipo)
+			}
+		} else if inventarioAdicionar(inv, p.tipo) {
+			t.Errorf("passo %d: %s adicionado sem espaço", i, p.tipo)
+		}
+		if !reflect.DeepEqual(inv.Pilhas, p.esperado) {
+			t.Fatalf("passo %d: pilhas %v, esperadas %v", i, inv.Pilhas, p.esperado)
+		}
+	}
+
+	for _, espaco := range []int{-1, 2} {
+		if _, ok := inventarioRetirar(inv, espaco); ok {
+			t.Errorf("retirado do espaço %d, que não existe", espaco)
+		}
+	}
+}
+
+func TestInventarioColetarEUsar(t *testing.T) {
+	jogo := interacaoJogoTeste(t, "▤▤▤▤▤▤\n▤☺¤★¤▤\n▤▤▤▤▤▤\n")
+	jogo.Inventario.Capacidade, jogo.Inventario.MaxPilha = 2, 1
+
+	// O segundo item de invisibilidade não cabe: fica no mapa
+	interacaoJogar(jogo, "ddd")
+	esperado := []PilhaItem{{ItemInvisibilidade, 1}, {ItemPuloDuplo, 1}}
+	if !reflect.DeepEqual(jogo.Inventario.Pilhas, esperado) || jogo.EstrelasColetadas != 1 {
+		t.Fatalf("pilhas %v, estrelas %d", jogo.Inventario.Pilhas, jogo.EstrelasColetadas)
+	}
+	if jogo.UltimoVisitado != InvisibilityItem || interacaoUltimaMensagem(jogo) != traduzir("inventario.cheio") {
+		t.Errorf("sob o personagem %q, mensagem %q", jogo.UltimoVisitado.simbolo, interacaoUltimaMensagem(jogo))
+	}
+
+	// Usar o item do espaço 1 aplica a invisibilidade e abre espaço para o outro
+	interacaoJogar(jogo, "u")
+	if jogo.InvisibleSteps != InvisibilityDuration {
+		t.Errorf("passos invisíveis = %d, esperado %d", jogo.InvisibleSteps, InvisibilityDuration)
+	}
+	interacaoJogar(jogo, "ad")
+	esperado = []PilhaItem{{ItemPuloDuplo, 1}, {ItemInvisibilidade, 1}}
+	if !reflect.DeepEqual(jogo.Inventario.Pilhas, esperado) {
+		t.Errorf("pilhas %v, esperadas %v", jogo.Inventario.Pilhas, esperado)
+	}
+}
+
+func TestInventarioEntreNiveis(t *testing.T) {
+	dir := t.TempDir()
+	var mapas []string
+	for i, mapa := range []string{"▤▤▤▤\n▤☺⚑▤\n▤▤▤▤\n", "▤▤▤▤\n▤☺ ▤\n▤▤▤▤\n"} {
+		nome := filepath.Join(dir, "nivel"+string(rune('1'+i))+".txt")
+		if err := os.WriteFile(nome, []byte(mapa), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mapas = append(mapas, nome)
+	}
+	cfg := ConfigJogo{Opcoes: configuracoesPadrao(), Semente: 1}
+
+	primeiro, err := jogoNovoNivel(mapas, 0, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tipo := range []string{ItemPuloDuplo, ItemPuloDuplo, ItemInvisibilidade} {
+		inventarioAdicionar(primeiro.Inventario, tipo)
+	}
+	esperado := []PilhaItem{{ItemPuloDuplo, 2}, {ItemInvisibilidade, 1}}
+
+	segundo, err := jogoNovoNivel(mapas, 1, cfg, primeiro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(segundo.Inventario.Pilhas, esperado) {
+		t.Errorf("pilhas no nível seguinte %v, esperadas %v", segundo.Inventario.Pilhas, esperado)
+	}
+	// Usar um item no novo nível gasta do inventário da partida
+	interacaoJogar(segundo, "u")
+	if segundo.DoubleJumps != PulosDuplosPorEstrela || segundo.Inventario.Pilhas[0].Quantidade != 1 {
+		t.Errorf("pulos %d, pilhas %v", segundo.DoubleJumps, segundo.Inventario.Pilhas)
+	}
+
+	// Uma partida nova começa com o inventário vazio
+	novo, err := jogoNovoNivel(mapas, 1, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(novo.Inventario.Pilhas) != 0 {
+		t.Errorf("partida nova com itens: %v", novo.Inventario.Pilhas)
+	}
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"math/rand"
 	"os"
+	"time"
 )
 
 // Elemento representa qualquer objeto do mapa (parede, personagem, vegetação, etc)
@@ -11,54 +14,130 @@ type Elemento struct {
 	simbolo  rune
 	cor      Cor
 	corFundo Cor
-	tangivel bool
 }
 
 type Jogo struct {
 	Mapa           [][]Elemento // grade 2D representando o mapa
 	PosX, PosY     int          // posição atual do personagem
 	UltimoVisitado Elemento     // elemento que estava na posição do personagem antes de mover
-	StatusMsg      string       // mensagem para a barra de status
+	Mensagens      *RegistroMensagens // mensagens exibidas no HUD e no registro completo
 	InvisibleSteps int          // contador de invisibilidade do personagem (em passos)
 	DoubleJumps    int          // contador de pulos duplos restantes
+	PowerSteps     int          // movimentos restantes do poder que assusta os monstros
 	Monstro        *Monster     // instância do monstro
 	InvisibilityItems []*Invisibility // lista de itens de invisibilidade
 	Stars []*Star // lista de estrelas
 	GameEvents     chan GameEvent     // canal para eventos do jogo
 	PlayerState    chan PlayerState   // canal para estado do jogador
 	PlayerAlerts   chan PlayerAlert   // canal para alertas do jogador
-	PlayerCollects chan PlayerCollect // canal para coletas do jogador
 	StarCommands   chan StarCommand   // canal para comandos das estrelas
 	MapMutex       chan chan bool     // canal para exclusão mútua do mapa
+	Camera         Camera             // janela visível do mapa
+	DirX, DirY     int                // direção para a qual o personagem está virado
+	Chaves         map[string]int     // chaves carregadas, por identificador
+	Interativos    map[Position]*Interativo // tiles interativos declarados no mapa
+	Inventario     *Inventario        // itens guardados, mantidos entre níveis
+	NivelConcluido bool               // personagem chegou à saída do nível
+	Mapas          []string           // arquivos de mapa da campanha
+	Nivel          int                // índice do mapa atual em Mapas
+	ArquivoSave    string             // arquivo onde o jogo é salvo ("" desativa)
+	Semente        int64              // semente dos números aleatórios do nível
+	Rand           *rand.Rand         // gerador usado pelas ações do jogador
+	Gravacao       *Gravacao          // gravação das entradas, se ativa
+	Reproducao     *Reproducao        // partida gravada em reprodução, se houver
+	Partida        *Partida           // pontuação e tempo acumulados na campanha
+	FimDeJogo      bool               // personagem foi pego pelo monstro
+	Pausas         []chan bool        // canais de pausa das goroutines dos elementos
+	Congelamentos  int                // congelamentos em andamento (telas abertas, save)
+	Opcoes         *Configuracoes     // opções do jogador, alteráveis no menu de pausa
+	InicioX, InicioY  int             // posição inicial, onde o personagem reaparece
+	EstrelasTotal     int             // estrelas existentes no mapa ao carregar o nível
+	EstrelasColetadas int             // estrelas guardadas no inventário neste nível
+	EstadoMonstros map[string]MonsterState // último estado informado por cada monstro
+	EpocaMonstro   int                     // mudanças de estado impostas pelo jogo ao monstro
+	PosicaoAvisada Position                // última posição do personagem enviada às estrelas
+	Neblina        *Neblina                // campo de visão e tiles explorados (nil sem neblina)
+	Depuracao      bool                    // exibe a sobreposição de depuração
+	Console        *Console                // histórico e saída do console de desenvolvimento
+	Contexto       context.Context         // contexto das goroutines do nível em execução
+	Constelacao    *Constelacao            // estrelas em execução, para as mensagens entre elas
+	Sinais         []SinalEstrela          // mensagens recentes entre estrelas, desenhadas no mapa
+	Geradores      []*Gerador              // geradores de itens declarados no mapa
+	BlocosIniciais []Position              // posições dos blocos ao carregar o nível, para reiniciar o quebra-cabeça
+	Jatos          []Position              // jatos de chamas do mapa, cada um com sua goroutine
+	PresoNaLama    bool                    // o próximo movimento do personagem é gasto para sair da lama
+	Portais        map[Position]Position   // tile ligado a cada portal
+	SoPersonagem   map[string]bool         // identificadores dos pares de portais que não levam monstros
+	RecargaPortais map[string]int          // movimentos até cada entidade poder usar um portal de novo
 }
 
 // Elementos visuais do jogo
 var (
-	Personagem          = Elemento{'☺', CorCinzaEscuro, CorPadrao, true}
-	Inimigo             = Elemento{'☠', CorVermelho, CorPadrao, true}
-	Parede              = Elemento{'▤', CorParede, CorFundoParede, true}
-	Vegetacao           = Elemento{'♣', CorVerde, CorPadrao, false}
-	Vazio               = Elemento{' ', CorPadrao, CorPadrao, false}
-	InvisibilityItem    = Elemento{'¤', CorAmarelo, CorPadrao, false}
-	PersonagemInvisivel = Elemento{'☺', CorTexto, CorPadrao, true}
-	StarElementVisible   = Elemento{'★', CorAmarelo, CorPadrao, false}
-	StarElementInvisible = Elemento{' ', CorPadrao, CorPadrao, false}
-	StarElementPulsing   = Elemento{'✦', CorCinzaEscuro, CorPadrao, false}
-	StarElementCharging  = Elemento{'◉', CorVermelho, CorPadrao, false}
+	Personagem          = Elemento{'☺', CorCinzaEscuro, CorPadrao}
+	Inimigo             = Elemento{'☠', CorVermelho, CorPadrao}
+	InimigoAssustado    = Elemento{'☠', CorAzul, CorPadrao}
+	Parede              = Elemento{'▤', CorParede, CorFundoParede}
+	Vegetacao           = Elemento{'♣', CorVerde, CorPadrao}
+	Vazio               = Elemento{' ', CorPadrao, CorPadrao}
+	InvisibilityItem    = Elemento{'¤', CorAmarelo, CorPadrao}
+	PersonagemInvisivel = Elemento{'☺', CorTexto, CorPadrao}
+	StarElementVisible   = Elemento{'★', CorAmarelo, CorPadrao}
+	StarElementInvisible = Elemento{' ', CorPadrao, CorPadrao}
+	StarElementPulsing   = Elemento{'✦', CorCinzaEscuro, CorPadrao}
+	StarElementCharging  = Elemento{'◉', CorVermelho, CorPadrao}
+	Chave                = Elemento{'⚷', CorAmarelo, CorPadrao}
+	PortaTrancada        = Elemento{'▣', CorAmarelo, CorPadrao}
+	PortaAberta          = Elemento{'▭', CorAmarelo, CorPadrao}
+	AlavancaDesligada    = Elemento{'⌐', CorVermelho, CorPadrao}
+	AlavancaLigada       = Elemento{'¬', CorVerde, CorPadrao}
+	PortaoFechado        = Elemento{'▦', CorCinzaEscuro, CorPadrao}
+	PortaoAberto         = Elemento{'▫', CorCinzaEscuro, CorPadrao}
+	Saida                = Elemento{'⚑', CorVerde, CorPadrao}
+	Bloco                = Elemento{'■', CorAmarelo, CorPadrao}
+	PlacaPressao         = Elemento{'◇', CorCinzaEscuro, CorPadrao}
+	BlocoSobrePlaca      = Elemento{'◆', CorVerde, CorPadrao}
+	Espinhos             = Elemento{'▲', CorVermelho, CorPadrao}
+	Buraco               = Elemento{'○', CorCinzaEscuro, CorPadrao}
+	Lama                 = Elemento{'≈', CorAmarelo, CorPadrao}
+	JatoApagado          = Elemento{'⌂', CorCinzaEscuro, CorPadrao}
+	JatoAceso            = Elemento{'♨', CorVermelho, CorPadrao}
+	Portal               = Elemento{'◎', CorAzul, CorPadrao}
 )
 
 func jogoNovo() Jogo {
+	semente := time.Now().UnixNano()
 	return Jogo{
 		UltimoVisitado: Vazio,
 		GameEvents:     make(chan GameEvent, 10),
 		PlayerState:    make(chan PlayerState, 10),
 		PlayerAlerts:   make(chan PlayerAlert, 10),
-		PlayerCollects: make(chan PlayerCollect, 10),
 		StarCommands:   make(chan StarCommand, 10),
 		MapMutex:       make(chan chan bool, 1),
+		Camera:         cameraNova(CameraZonaMortaPadraoX, CameraZonaMortaPadraoY),
+		DirY:           1,
+		Chaves:         make(map[string]int),
+		Interativos:    make(map[Position]*Interativo),
+		EstadoMonstros: make(map[string]MonsterState),
+		Portais:        make(map[Position]Position),
+		SoPersonagem:   make(map[string]bool),
+		RecargaPortais: make(map[string]int),
+		Mensagens:      registroNovo(),
+		Inventario:     inventarioNovo(),
+		ArquivoSave:    ArquivoSavePadrao,
+		Semente:        semente,
+		Rand:           rand.New(rand.NewSource(semente)),
+		Partida:        partidaNova(),
+		Opcoes:         configuracoesPadrao(),
+		Constelacao:    constelacaoNova(),
 	}
 }
 
+// Define a semente do nível; deve ser chamada antes de carregar o mapa
+func jogoDefinirSemente(jogo *Jogo, semente int64) {
+	jogo.Semente = semente
+	jogo.Rand = rand.New(rand.NewSource(semente))
+}
+
 // Lê um arquivo texto linha por linha e constrói o mapa do jogo
 func jogoCarregarMapa(nome string, jogo *Jogo) error {
 	arq, err := os.Open(nome)
@@ -71,38 +150,15 @@ func jogoCarregarMapa(nome string, jogo *Jogo) error {
 	y := 0
 	for scanner.Scan() {
 		linha := scanner.Text()
-		var linhaElems []Elemento
-		for x, ch := range linha {
-			e := Vazio
-			switch ch {
-			case Parede.simbolo:
-				e = Parede
-			case Inimigo.simbolo:
-				e = Vazio // Não desenhar o inimigo no mapa, será desenhado separadamente
-				// Inicializar monstro se ainda não foi criado
-				if jogo.Monstro == nil {
-					jogo.Monstro = &Monster{
-						current_position: Position{X: x, Y: y},
-						state:            Patrolling,
-						destiny_position: Position{X: x + 5, Y: y + 5},
-						id:               "monster_1",
-					}
-				}
-			case Vegetacao.simbolo:
-				e = Vegetacao
-			case InvisibilityItem.simbolo:
-				e = InvisibilityItem
-				invisItem := &Invisibility{
-					X: x,
-					Y: y,
-				}
-				jogo.InvisibilityItems = append(jogo.InvisibilityItems, invisItem)
-			case '★':
-				e = StarElementVisible
-			case Personagem.simbolo:
-				jogo.PosX, jogo.PosY = x, y // registra a posição inicial do personagem
+		if len(linha) > 0 && linha[0] == PrefixoMetadados {
+			if err := jogoLerMetadado(jogo, linha); err != nil {
+				return err
 			}
-			linhaElems = append(linhaElems, e)
+			continue
+		}
+		var linhaElems []Elemento
+		for x, ch := range []rune(linha) { // índice por caractere, não por byte
+			linhaElems = append(linhaElems, tilesCarregar(jogo, ch, x, y))
 		}
 		jogo.Mapa = append(jogo.Mapa, linhaElems)
 		y++
@@ -110,7 +166,7 @@ func jogoCarregarMapa(nome string, jogo *Jogo) error {
 	if err := scanner.Err(); err != nil {
 		return err
 	}
-	return nil
+	return jogoLigarPortais(jogo)
 }
 
 // Verifica se o personagem pode se mover para a posição (x, y)
@@ -123,11 +179,7 @@ func jogoPodeMoverPara(jogo *Jogo, x, y int) bool {
 		return false
 	}
 
-	if jogo.Mapa[y][x].tangivel {
-		return false
-	}
-
-	return true
+	return tileTipo(jogo.Mapa[y][x]).Passavel
 }
 
 // Move um elemento para a nova posição
@@ -150,13 +202,28 @@ func (j *Jogo) elementoJogador() Elemento {
 	return Personagem
 }
 
-// Processa eventos vindos do monstro
+// Processa os eventos pendentes (monstro, itens e interações do jogador).
+// Eventos gerados durante o tratamento ficam para a próxima chamada. Na
+// reprodução, os eventos vêm da gravação e os do canal são descartados.
 func jogoProcessarEventos(jogo *Jogo) {
-	select {
-	case event := <-jogo.GameEvents:
-		jogoTratarEvento(jogo, event)
-	default:
-		// Não há eventos para processar
+	if jogo.Reproducao != nil {
+		for len(jogo.GameEvents) > 0 {
+			<-jogo.GameEvents
+		}
+		reproducaoTratarEventos(jogo, jogo.Reproducao)
+		return
+	}
+	for pendentes := len(jogo.GameEvents); pendentes > 0; pendentes-- {
+		select {
+		case event := <-jogo.GameEvents:
+			if jogo.Gravacao != nil {
+				gravacaoRegistrarEvento(jogo.Gravacao, event, false)
+			}
+			jogoTratarEvento(jogo, event)
+		default:
+			// Não há eventos para processar
+			return
+		}
 	}
 }
 
@@ -167,12 +234,17 @@ func jogoTratarEvento(jogo *Jogo, event GameEvent) {
 		if data, ok := event.Data.(MonsterMoveData); ok {
 			// Verificar se o movimento é válido
 			if jogoPodeMoverPara(jogo, data.NewX, data.NewY) {
-				// Atualizar posição do monstro
-				if jogo.Monstro != nil && jogo.Monstro.id == data.MonsterID {
-					jogo.Monstro.current_position = Position{X: data.NewX, Y: data.NewY}
+				// Atualizar posição do monstro. Ele anda um tile por vez: um passo
+				// que não parte de onde ele está (pedido antes de um portal o levar
+				// para outro lugar) é descartado
+				if jogo.Monstro != nil && jogo.Monstro.id == data.MonsterID &&
+					monsterAdjacente(jogo.Monstro.current_position, Position{X: data.NewX, Y: data.NewY}) {
+					jogo.Monstro.definirPosicao(Position{X: data.NewX, Y: data.NewY})
+					jogoRecarregarPortal(jogo, data.MonsterID)
+					jogoMonstroEntrarTile(jogo)
 
 					// Verificar colisão com jogador
-					if data.NewX == jogo.PosX && data.NewY == jogo.PosY {
+					if jogo.Monstro.current_position == (Position{X: jogo.PosX, Y: jogo.PosY}) {
 						// Enviar evento de colisão
 						collisionEvent := GameEvent{
 							Type: "monster_collision",
@@ -182,28 +254,45 @@ func jogoTratarEvento(jogo *Jogo, event GameEvent) {
 								"type": "movement",
 							},
 						}
-						select {
-						case jogo.GameEvents <- collisionEvent:
-						default:
-						}
+						enviarOuDescartar(jogo.GameEvents, collisionEvent)
 					}
 				}
 			}
 		}
 	case "monster_collision":
-		jogo.StatusMsg = "Pego pelo monstro!"
-	case "monster_timeout":
-		if data, ok := event.Data.(map[string]interface{}); ok {
-			if message, hasMsg := data["message"]; hasMsg {
-				if msgStr, isString := message.(string); isString {
-					jogo.StatusMsg = "Alerta: " + msgStr
-				}
+		// Ignora colisões já resolvidas (o personagem pode ter reaparecido)
+		if jogo.Monstro != nil && jogo.Monstro.current_position == (Position{X: jogo.PosX, Y: jogo.PosY}) {
+			switch {
+			case jogoMonstroCapturado(jogo):
+				// O monstro capturado não oferece perigo até reaparecer
+			case jogoMonstroAssustado(jogo):
+				jogoCapturarMonstro(jogo)
+			default:
+				personagemPerderVida(jogo)
 			}
 		}
+	case "monster_route":
+		if data, ok := event.Data.(MonsterRouteData); ok && jogo.Monstro != nil && jogo.Monstro.id == data.MonsterID {
+			jogoEnviarRotaMonstro(jogo, data.Para)
+		}
+	case "monster_respawn":
+		// O jogo é quem move o monstro, também quando ele volta à origem
+		if data, ok := event.Data.(MonsterMoveData); ok && jogo.Monstro != nil && jogo.Monstro.id == data.MonsterID {
+			jogo.Monstro.definirPosicao(Position{X: data.NewX, Y: data.NewY})
+			jogoMensagem(jogo, SeveridadeAviso, "monstro.reapareceu")
+		}
+	case "monster_state":
+		// Estados informados antes de o monstro receber a última mudança imposta
+		// pelo jogo estão desatualizados
+		if data, ok := event.Data.(MonsterStateData); ok && data.Epoca >= jogo.EpocaMonstro {
+			jogo.EstadoMonstros[data.MonsterID] = data.State
+		}
+	case "monster_timeout":
+		jogoMensagem(jogo, SeveridadeAviso, "monstro.perdeu_rastro")
 	case EventApplyInvisibility:
 		if data, ok := event.Data.(InvisibilityApplied); ok {
 			jogo.InvisibleSteps = data.Duration
-			jogo.StatusMsg = "Invisibilidade coletada!"
+			jogoMensagem(jogo, SeveridadeInfo, "invisibilidade.ativada")
 		}
 	case EventRemoveElement:
 		// Remover item do mapa
@@ -225,41 +314,84 @@ func jogoTratarEvento(jogo *Jogo, event GameEvent) {
 		}
 	case EventStarCollected:
 		if data, ok := event.Data.(StarCollectedData); ok {
-			jogo.StatusMsg = fmt.Sprintf("Estrela coletada! %s +%d", data.BonusType, data.Value)
+			jogoMensagem(jogo, SeveridadeInfo, "estrela.coletada", traduzir("estrela.bonus."+data.BonusType), data.Value)
+			if data.BonusType == "power" {
+				jogoAtivarPoder(jogo, PowerDuration)
+			}
 		}
 	case EventStarStateChange:
 		if data, ok := event.Data.(StarStateChangeData); ok {
-			jogo.StatusMsg = fmt.Sprintf("Estrela %s mudou de estado", data.StarID)
+			jogoMensagem(jogo, SeveridadeDetalhe, "estrela.estado", data.StarID)
 		}
 	case EventStarPulse:
 		if data, ok := event.Data.(StarPulseData); ok {
-			jogo.StatusMsg = fmt.Sprintf("Estrela pulsando (%d pulsos)", data.PulseCount)
+			jogoMensagem(jogo, SeveridadeDetalhe, "estrela.pulsando", data.PulseCount)
 		}
 	case EventStarCharged:
 		if data, ok := event.Data.(StarChargedData); ok {
-			jogo.StatusMsg = fmt.Sprintf("Estrela carregada! Energia: %d", data.Energy)
+			jogoMensagem(jogo, SeveridadeDetalhe, "estrela.carregada", data.Energy)
 		}
 	case EventStarTimeout:
 		if data, ok := event.Data.(StarTimeoutData); ok {
-			jogo.StatusMsg = data.Message
+			jogoMensagem(jogo, SeveridadeDetalhe, "estrela.timeout", data.StarID, traduzir("estrela.acao."+data.Action))
 		}
 	case EventStarCommunicate:
 		if data, ok := event.Data.(StarCommunicationData); ok {
-			jogo.StatusMsg = fmt.Sprintf("Estrelas comunicando: %s", data.Message)
+			jogoRegistrarSinal(jogo, data)
+			jogoMensagem(jogo, SeveridadeDetalhe, "estrela.comunicando", data.FromStarID, data.ToStarID, traduzir("estrela.sinal."+data.Message))
+		}
+	case EventPortaAberta, EventAlavancaAcionada, EventChaveColetada:
+		jogoTratarEventoInteracao(jogo, event)
+	case EventBlocoEmpurrado:
+		if data, ok := event.Data.(BlocoEmpurradoData); ok {
+			jogoTratarBlocoEmpurrado(jogo, data)
+		}
+	case EventGerarItem:
+		if data, ok := event.Data.(GerarItemData); ok && data.Indice < len(jogo.Geradores) {
+			jogoGerarItem(jogo, jogo.Geradores[data.Indice], data.Geracao)
+		}
+	case EventJatoChamas:
+		if data, ok := event.Data.(JatoChamasData); ok {
+			jogoTratarJatoChamas(jogo, data)
 		}
 	case "ApplyDoubleJump":
-		// Boost de pulo duplo foi coletado
+		// Boost de pulo duplo foi usado do inventário
 		if data, ok := event.Data.(DoubleJumpApplied); ok {
-			jogo.DoubleJumps = data.Jumps
-			jogo.StatusMsg = "Estrela coletada! Pulo duplo ativado!"
+			jogo.DoubleJumps += data.Jumps
+			jogoMensagem(jogo, SeveridadeInfo, "pulo.ativado", jogo.DoubleJumps)
 		}
 	}
 }
 
+// Verifica se o personagem andou para a posição do monstro
+func jogoVerificarColisao(jogo *Jogo) {
+	if jogo.Monstro != nil && jogo.Monstro.current_position == (Position{X: jogo.PosX, Y: jogo.PosY}) {
+		jogoTratarEvento(jogo, GameEvent{Type: "monster_collision"})
+	}
+}
+
 func jogoEnviarEstadoJogador(jogo *Jogo) {
+	estado := PlayerState{X: jogo.PosX, Y: jogo.PosY}
 	select {
-	case jogo.PlayerState <- PlayerState{X: jogo.PosX, Y: jogo.PosY}:
+	case jogo.PlayerState <- estado:
 	default:
+		descartesRegistrar("player_state")
+	}
+	// Cada estrela perto do personagem recebe a própria cópia da posição, para
+	// avisar as vizinhas. As que estavam perto da posição anterior também
+	// recebem, para perceber que ele se afastou.
+	anterior := jogo.PosicaoAvisada
+	jogo.PosicaoAvisada = Position(estado)
+	for _, star := range constelacaoEstrelas(jogo.Constelacao) {
+		if abs(star.X-estado.X)+abs(star.Y-estado.Y) > RaioAvisoEstrelas &&
+			abs(star.X-anterior.X)+abs(star.Y-anterior.Y) > RaioAvisoEstrelas {
+			continue
+		}
+		select {
+		case star.Comandos <- StarCommand{Type: "player_moved", Target: star.ID, Data: estado}:
+		default:
+			descartesRegistrar("player_state")
+		}
 	}
 }
 
@@ -271,11 +403,7 @@ func jogoEnviarAlerta(jogo *Jogo, tipoAlerta string) {
 			"y": jogo.PosY,
 		},
 	}
-
-	select {
-	case jogo.PlayerAlerts <- alert:
-	default:
-	}
+	jogoAlertarMonstro(jogo, alert)
 }
 
 // Gerencia exclusão mútua do mapa usando canais
@@ -285,28 +413,156 @@ func jogoGerenciarMapMutex(jogo *Jogo) {
 	}
 }
 
-func jogoEnviarComandoEstrela(jogo *Jogo, command StarCommand) {
-	select {
-	case jogo.StarCommands <- command:
-	default:
+// Envia um comando à estrela indicada em Target ou, sem Target, a todas as
+// estrelas em execução. Retorna false se o comando não foi entregue a nenhuma.
+func jogoEnviarComandoEstrela(jogo *Jogo, command StarCommand) bool {
+	var destinos []*Star
+	if command.Target != "" {
+		star := jogoEstrelaPorID(jogo, command.Target)
+		if star == nil {
+			return false
+		}
+		destinos = append(destinos, star)
+	} else {
+		destinos = constelacaoEstrelas(jogo.Constelacao)
+	}
+	entregue := false
+	for _, star := range destinos {
+		select {
+		case star.Comandos <- command:
+			entregue = true
+		default:
+			descartesRegistrar("star_command")
+		}
+	}
+	return entregue
+}
+
+// Registra a estrela na constelação e inicia a sua goroutine no nível em
+// execução; na reprodução, a estrela só é registrada
+func jogoIniciarEstrela(jogo *Jogo, star *Star) {
+	star.Constelacao = jogo.Constelacao
+	constelacaoRegistrar(jogo.Constelacao, star)
+	if jogo.Reproducao != nil {
+		return
+	}
+	star.Coletas = make(chan PlayerCollect, 1)
+	// A posição do jogador chega pelos comandos da estrela; o canal PlayerState fica com o monstro
+	go star.Run(jogo.Contexto, jogo.GameEvents, nil, star.Coletas, jogo.StarCommands, jogo.MapMutex, jogoNovoCanalPausa(jogo))
+}
+
+// Cria uma estrela ativa no tile, com o seu próprio comportamento
+func jogoCriarEstrela(jogo *Jogo, x, y int) *Star {
+	jogo.Mapa[y][x] = StarElementVisible
+	star := NewStar(x, y, jogoNovoIDEstrela(jogo))
+	jogo.Stars = append(jogo.Stars, star)
+	jogo.EstrelasTotal++
+	jogoIniciarEstrela(jogo, star)
+	return star
+}
+
+// Cria um item de invisibilidade no tile e inicia a sua goroutine
+func jogoCriarInvisibilidade(jogo *Jogo, x, y int) {
+	jogo.Mapa[y][x] = InvisibilityItem
+	item := &Invisibility{X: x, Y: y}
+	jogo.InvisibilityItems = append(jogo.InvisibilityItems, item)
+	jogoIniciarInvisibilidade(jogo, item)
+}
+
+// Inicia a goroutine do item de invisibilidade no nível em execução, fora da reprodução
+func jogoIniciarInvisibilidade(jogo *Jogo, item *Invisibility) {
+	if jogo.Reproducao != nil {
+		return
+	}
+	item.Coletas = make(chan PlayerCollect, 1)
+	go item.Run(jogo.Contexto, jogo.GameEvents, item.Coletas)
+}
+
+// Entrega a coleta aos itens do tile coletado. Um item recriado por um
+// gerador divide o tile com o item coletado antes, cuja goroutine já terminou;
+// por isso a coleta vai para todos eles, e só a goroutine viva a lê.
+func jogoEntregarColeta(jogo *Jogo, coleta PlayerCollect) {
+	var canais []chan PlayerCollect
+	for _, star := range jogo.Stars {
+		if star.X == coleta.X && star.Y == coleta.Y && star.Coletas != nil {
+			canais = append(canais, star.Coletas)
+		}
+	}
+	for _, item := range jogo.InvisibilityItems {
+		if item.X == coleta.X && item.Y == coleta.Y && item.Coletas != nil {
+			canais = append(canais, item.Coletas)
+		}
 	}
+	entregue := false
+	for _, canal := range canais {
+		select {
+		case canal <- coleta:
+			entregue = true
+		default:
+		}
+	}
+	if !entregue {
+		descartesRegistrar("player_collect")
+	}
+}
+
+// Primeiro identificador "star_N" ainda não usado
+func jogoNovoIDEstrela(jogo *Jogo) string {
+	for n := len(jogo.Stars) + 1; ; n++ {
+		id := fmt.Sprintf("star_%d", n)
+		if jogoEstrelaPorID(jogo, id) == nil {
+			return id
+		}
+	}
+}
+
+func jogoEstrelaPorID(jogo *Jogo, id string) *Star {
+	for _, star := range jogo.Stars {
+		if star.ID == id {
+			return star
+		}
+	}
+	return nil
 }
 
-// Remoção da estrela "sob" o jogador quando consumida.
+// Remoção da estrela "sob" o jogador quando guardada no inventário.
 func ConsumirItemEstrela(jogo *Jogo) bool {
-	if jogo.UltimoVisitado.simbolo == StarElementVisible.simbolo {
+	if personagemGuardarItem(jogo, ItemPuloDuplo) {
 		jogo.UltimoVisitado = Vazio
+		jogo.EstrelasColetadas++
 		return true
 	}
 	return false
 }
 
+// Tiles que podem ficar na grade, além do vazio
+func jogoElementosDoMapa() []Elemento {
+	var elems []Elemento
+	for _, t := range tiposTile {
+		if !t.Marcador && t.Elemento.simbolo != Vazio.simbolo {
+			elems = append(elems, *t.Elemento)
+		}
+	}
+	return elems
+}
+
+// Retorna o tile do mapa correspondente a um símbolo (Vazio se desconhecido)
+func jogoElementoPorSimbolo(simbolo rune) Elemento {
+	for _, e := range jogoElementosDoMapa() {
+		if e.simbolo == simbolo {
+			return e
+		}
+	}
+	return Vazio
+}
+
 func jogoGetStarElement(star *Star) Elemento {
-	if !star.IsVisible {
+	s := star.snapshot()
+	if !s.IsVisible {
 		return StarElementInvisible
 	}
 
-	switch star.State {
+	switch s.State {
 	case StarVisible:
 		return StarElementVisible
 	case StarInvisible:
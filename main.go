@@ -3,43 +3,367 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 )
 
 func main() {
+	// Opções gravadas pelo jogador servem de padrão para a linha de comando
+	opcoes, errOpcoes := configuracoesCarregar()
+
+	// Tamanho da zona morta da câmera configurável por linha de comando
+	flag.IntVar(&opcoes.ZonaMortaX, "zona-x", opcoes.ZonaMortaX, "zona morta horizontal da câmera")
+	flag.IntVar(&opcoes.ZonaMortaY, "zona-y", opcoes.ZonaMortaY, "zona morta vertical da câmera")
+	arquivoSave := flag.String("save", ArquivoSavePadrao, "arquivo para salvar e continuar o jogo")
+	semente := flag.Int64("semente", 0, "semente dos números aleatórios (0 = aleatória)")
+	arquivoGravacao := flag.String("gravar", "", "grava as entradas da partida neste arquivo de replay")
+	velocidade := flag.Float64("velocidade", 1, "velocidade da reprodução no modo replay")
+	idioma := flag.String("idioma", opcoes.Idioma, "idioma dos textos: pt-BR ou en (vazio = do sistema, via LANG)")
+	flag.BoolVar(&opcoes.Neblina, "neblina", opcoes.Neblina, "neblina de guerra: mostra só o que o personagem vê")
+	depurar := flag.Bool("depurar", false, "começa com a sobreposição de depuração ativa (alternada com F3)")
+	nomeTema := flag.String("tema", opcoes.Tema, "tema visual: "+strings.Join(temasDisponiveis(), ", "))
+	arquivoTiles := flag.String("tiles", ArquivoTilesPadrao, "arquivo de definições de tiles (JSON)")
+	flag.Parse()
+	idiomaAtual = idiomaEscolher(*idioma)
+	// O aviso das configurações espera o idioma, que pode vir delas
+	if errOpcoes != nil {
+		fmt.Fprintln(os.Stderr, traduzir("aviso.configuracoes", errOpcoes))
+	}
+	if err := tilesCarregarDefinicoes(*arquivoTiles); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if t, err := temaEscolher(*nomeTema); err != nil {
+		fmt.Fprintln(os.Stderr, traduzir("aviso", err))
+	} else {
+		temaAtual = t
+	}
+
+	// Conferência dos catálogos: jogo verificar-textos
+	if flag.Arg(0) == "verificar-textos" {
+		if err := textosVerificar(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(traduzir("textos.completos", strings.Join(idiomasDisponiveis(), ", ")))
+		return
+	}
+
+	// Modo editor: jogo edit <mapa>
+	if flag.Arg(0) == "edit" {
+		if flag.NArg() < 2 {
+			fmt.Fprintln(os.Stderr, traduzir("uso.editor"))
+			os.Exit(2)
+		}
+		if err := editorExecutar(flag.Arg(1)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg := ConfigJogo{
+		Opcoes:      opcoes,
+		ArquivoSave: *arquivoSave,
+		Semente:     *semente,
+		Entrada:     func() EventoTeclado { return interfaceLerEventoTeclado(opcoes.Teclas) },
+		Depuracao:   *depurar,
+	}
+	if cfg.Semente == 0 {
+		cfg.Semente = time.Now().UnixNano()
+	}
+
+	// Exportação do mapa para SVG ou HTML: jogo export <mapa> [-formato svg|html]
+	if flag.Arg(0) == "export" {
+		if err := exportarComando(flag.Args()[1:], cfg.Semente); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Continuar jogo salvo: jogo load [arquivo]
+	if flag.Arg(0) == "load" {
+		nome := *arquivoSave
+		if flag.NArg() > 1 {
+			nome = flag.Arg(1)
+		}
+		cfg.ArquivoSave = nome
+
+		interfaceIniciar()
+		defer interfaceFinalizar()
+		if err := jogoContinuar(cfg); err != nil {
+			interfaceFinalizar()
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Reprodução de uma partida gravada: jogo replay <arquivo>
+	if flag.Arg(0) == "replay" {
+		if flag.NArg() < 2 {
+			fmt.Fprintln(os.Stderr, traduzir("uso.replay"))
+			os.Exit(2)
+		}
+		gravacao, err := gravacaoCarregar(flag.Arg(1))
+		if err == nil {
+			cfg.Reproducao, err = reproducaoNova(gravacao)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		VelocidadeJogo = *velocidade
+		cfg.Semente = gravacao.Semente
+		cfg.ArquivoSave = "" // o replay não sobrescreve o save do jogador
+
+		jogo, err := jogoNovoNivel(gravacao.Mapas, gravacao.Nivel, cfg, nil)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		interfaceIniciar()
+		defer interfaceFinalizar()
+		cfg.Entrada = replayEntrada(cfg.Reproducao, opcoes.Teclas)
+		if _, err := jogoExecutarCampanha(jogo, cfg); err != nil {
+			interfaceFinalizar()
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Usa "mapa.txt" como arquivo padrão; vários mapas formam uma campanha jogada em sequência
+	mapas := []string{"mapa.txt"}
+	if flag.NArg() > 0 {
+		mapas = flag.Args()
+	}
+
 	// Inicializa a interface (termbox)
 	interfaceIniciar()
 	defer interfaceFinalizar()
 
-	// Usa "mapa.txt" como arquivo padrão ou lê o primeiro argumento
-	mapaFile := "mapa.txt"
-	if len(os.Args) > 1 {
-		mapaFile = os.Args[1]
+	for {
+		escolha := interfaceNavegarMenus(menuPrincipal(mapas, cfg))
+		var err error
+		switch {
+		case escolha == "novo" || strings.HasPrefix(escolha, "nivel:"):
+			nivel := 0
+			fmt.Sscanf(escolha, "nivel:%d", &nivel)
+			if *arquivoGravacao != "" {
+				cfg.Gravacao = gravacaoNova(cfg.Semente, mapas, nivel)
+			}
+			var jogo *Jogo
+			if jogo, err = jogoNovoNivel(mapas, nivel, cfg, nil); err == nil {
+				err = jogoJogarPartida(jogo, cfg)
+			}
+			if cfg.Gravacao != nil {
+				if errGravar := gravacaoSalvar(cfg.Gravacao, *arquivoGravacao); errGravar != nil && err == nil {
+					err = errGravar
+				}
+				cfg.Gravacao = nil
+			}
+			// Cada nova partida usa uma semente diferente, a menos que tenha sido fixada
+			if *semente == 0 {
+				cfg.Semente = time.Now().UnixNano()
+			}
+		case escolha == "continuar":
+			err = jogoContinuar(cfg)
+		case escolha == "recordes":
+			err = jogoMostrarRecordes(mapas)
+		default:
+			return
+		}
+		if err != nil {
+			interfaceFinalizar()
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// Menu principal; "Continuar" só aparece se houver um jogo salvo
+func menuPrincipal(mapas []string, cfg ConfigJogo) *Menu {
+	menu := &Menu{
+		Titulo: traduzir("menu.titulo"),
+		Opcoes: []OpcaoMenu{
+			{Rotulo: traduzir("menu.novo"), Resultado: "novo"},
+			{Rotulo: traduzir("menu.continuar"), Resultado: "continuar", Visivel: func() bool {
+				_, err := os.Stat(cfg.ArquivoSave)
+				return err == nil
+			}},
+			{Rotulo: traduzir("menu.selecionar_nivel"), Submenu: func() *Menu { return menuNiveis(mapas) }, Visivel: func() bool {
+				return len(mapas) > 1
+			}},
+			{Rotulo: traduzir("menu.recordes"), Resultado: "recordes"},
+			{Rotulo: traduzir("menu.opcoes"), Submenu: func() *Menu { return menuOpcoes(cfg.Opcoes) }},
+			{Rotulo: traduzir("menu.sair"), Resultado: "sair"},
+		},
+	}
+	return menu
+}
+
+// Lista os mapas da campanha para começar a partir de qualquer um deles
+func menuNiveis(mapas []string) *Menu {
+	menu := &Menu{Titulo: traduzir("menu.niveis")}
+	for i, mapa := range mapas {
+		menu.Opcoes = append(menu.Opcoes, OpcaoMenu{
+			Rotulo:    fmt.Sprintf("%d. %s", i+1, mapa),
+			Resultado: fmt.Sprintf("nivel:%d", i),
+		})
+	}
+	return menu
+}
+
+// Carrega o jogo salvo e continua a partida
+func jogoContinuar(cfg ConfigJogo) error {
+	jogo := jogoNovo()
+	if err := jogoCarregarSave(cfg.ArquivoSave, &jogo); err != nil {
+		return err
+	}
+	cfg.Semente = jogo.Partida.Semente
+	jogo.ArquivoSave = cfg.ArquivoSave
+	jogo.Opcoes = cfg.Opcoes
+	jogo.Camera = cameraNova(cfg.Opcoes.ZonaMortaX, cfg.Opcoes.ZonaMortaY)
+	jogo.Depuracao = cfg.Depuracao
+	return jogoJogarPartida(&jogo, cfg)
+}
+
+// Abre a tabela de recordes dos mapas atuais
+func jogoMostrarRecordes(mapas []string) error {
+	arq, err := recordesCarregar()
+	if err != nil {
+		return err
 	}
+	chave, _ := recordesChave(mapas)
+	return interfaceMostrarRecordes(arq, chave)
+}
+
+// ConfigJogo reúne as opções que valem para todos os níveis de uma partida
+type ConfigJogo struct {
+	Opcoes      *Configuracoes
+	ArquivoSave string
+	Semente     int64
+	Gravacao    *Gravacao            // gravação das entradas, se ativa
+	Reproducao  *Reproducao          // partida gravada em reprodução, se houver
+	Entrada     func() EventoTeclado // fonte das ações do jogador (teclado ou replay)
+	Depuracao   bool                 // começa com a sobreposição de depuração ativa
+}
 
-	// Inicializa o jogo
+// Carrega o mapa de um nível da campanha; inventário e pontuação vêm do nível anterior
+func jogoNovoNivel(mapas []string, nivel int, cfg ConfigJogo, anterior *Jogo) (*Jogo, error) {
 	jogo := jogoNovo()
-	if err := jogoCarregarMapa(mapaFile, &jogo); err != nil {
-		panic(err)
+	jogo.Mapas = mapas
+	jogo.Nivel = nivel
+	jogo.Opcoes = cfg.Opcoes
+	jogo.Camera = cameraNova(cfg.Opcoes.ZonaMortaX, cfg.Opcoes.ZonaMortaY)
+	jogo.ArquivoSave = cfg.ArquivoSave
+	jogo.Gravacao = cfg.Gravacao
+	jogo.Reproducao = cfg.Reproducao
+	jogo.Depuracao = cfg.Depuracao
+	if anterior != nil {
+		jogo.Depuracao = anterior.Depuracao
+		jogo.Console = anterior.Console
+		jogo.Inventario = anterior.Inventario
+		jogo.Mensagens = anterior.Mensagens
+		jogo.Partida = anterior.Partida
+	} else {
+		jogo.Partida.Semente = cfg.Semente
 	}
+	// Cada nível tem sua própria semente, derivada da semente da partida
+	jogoDefinirSemente(&jogo, cfg.Semente+int64(nivel))
+	if err := jogoCarregarMapa(mapas[nivel], &jogo); err != nil {
+		return nil, err
+	}
+	return &jogo, nil
+}
 
+// Joga a campanha e, se ela terminar (fim de jogo ou último nível concluído),
+// registra o recorde e descarta o save
+func jogoJogarPartida(jogo *Jogo, cfg ConfigJogo) error {
+	final, err := jogoExecutarCampanha(jogo, cfg)
+	if err != nil {
+		return err
+	}
+	if !final.FimDeJogo && !final.NivelConcluido {
+		return nil // jogador saiu; o save permite continuar depois
+	}
+	if final.ArquivoSave != "" {
+		os.Remove(final.ArquivoSave)
+	}
+	return jogoRegistrarRecorde(final)
+}
+
+// Joga o nível atual e os seguintes da campanha; retorna o estado do último nível jogado
+func jogoExecutarCampanha(jogo *Jogo, cfg ConfigJogo) (*Jogo, error) {
+	for {
+		concluido, err := jogoExecutarNivel(jogo, cfg.Entrada)
+		if err != nil {
+			return nil, err
+		}
+		if !concluido {
+			return jogo, nil
+		}
+		if jogo.Nivel+1 >= len(jogo.Mapas) {
+			return jogo, nil
+		}
+		proximo, err := jogoNovoNivel(jogo.Mapas, jogo.Nivel+1, cfg, jogo)
+		if err != nil {
+			return nil, err
+		}
+		jogo = proximo
+	}
+}
+
+// Executa um nível até o jogador sair ou chegar à saída; retorna true se o nível
+// foi concluído. O erro vem do save automático feito ao sair.
+func jogoExecutarNivel(jogo *Jogo, entrada func() EventoTeclado) (bool, error) {
 	// Criar contexto para controle das goroutines
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	jogo.Contexto = ctx
 
-	// Iniciar goroutine do monstro se ele existir
+	// Iniciar goroutine do monstro se ele existir; na reprodução, os eventos
+	// dos elementos vêm da gravação e os avisos a eles são descartados
 	if jogo.Monstro != nil {
-		go jogo.Monstro.Run(ctx, jogo.GameEvents, jogo.PlayerAlerts, jogo.PlayerState)
+		jogo.EstadoMonstros[jogo.Monstro.id] = jogo.Monstro.state
+	}
+	if jogo.Reproducao != nil {
+		go reproducaoDescartarAvisos(ctx, jogo)
+	} else if jogo.Monstro != nil {
+		go jogo.Monstro.Run(ctx, jogo.GameEvents, jogo.PlayerAlerts, jogo.PlayerState, jogoNovoCanalPausa(jogo))
 	}
 
 	// Iniciar goroutines dos itens de invisibilidade
 	for _, invisItem := range jogo.InvisibilityItems {
-		go invisItem.Run(ctx, jogo.GameEvents, jogo.PlayerCollects)
+		jogoIniciarInvisibilidade(jogo, invisItem)
 	}
 
 	// Iniciar goroutines das estrelas
 	for _, star := range jogo.Stars {
-		go star.Run(ctx, jogo.GameEvents, jogo.PlayerState, jogo.PlayerCollects, jogo.StarCommands, jogo.MapMutex)
+		jogoIniciarEstrela(jogo, star)
+	}
+
+	// Iniciar goroutines dos geradores de itens; o sorteio das posições é
+	// feito pelo jogo, também na reprodução
+	for i, g := range jogo.Geradores {
+		geradorIniciar(g, jogo.Semente, i)
+		if jogo.Reproducao == nil {
+			go g.Run(ctx, i, jogo.GameEvents, jogoNovoCanalPausa(jogo))
+		}
+	}
+
+	// Iniciar goroutines dos jatos de chamas; um save pode trazer jatos acesos
+	for _, p := range jogo.Jatos {
+		if jogo.Reproducao == nil {
+			aceso := jogo.Mapa[p.Y][p.X] == JatoAceso
+			go jatoCiclo(ctx, p, aceso, jogo.GameEvents, jogoNovoCanalPausa(jogo))
+		}
 	}
 
 	// Iniciar goroutine para gerenciar exclusão mútua do mapa
@@ -55,17 +379,57 @@ func main() {
 		}
 	}()
 
-	interfaceDesenharJogo(&jogo)
+	interfaceDesenharJogo(jogo)
 
 	// Loop principal de entrada
 	for {
-		evento := interfaceLerEventoTeclado()
-		if continuar := personagemExecutarAcao(evento, &jogo); !continuar {
-			cancel() 
-			break
+		evento := entrada()
+		switch evento.Tipo {
+		case "pausar":
+			// A pausa não entra na gravação; só a saída confirmada conta como ação
+			evento = EventoTeclado{}
+			if !jogoPausar(jogo) {
+				evento = EventoTeclado{Tipo: "sair"}
+			}
+		case "mensagens":
+			evento = EventoTeclado{}
+			retomar := jogoCongelar(jogo)
+			interfaceMostrarMensagens(jogo)
+			retomar()
+		case "depurar":
+			evento = EventoTeclado{}
+			jogo.Depuracao = !jogo.Depuracao
+		case "console":
+			evento = EventoTeclado{}
+			retomar := jogoCongelar(jogo)
+			interfaceConsole(jogo)
+			retomar()
+		case AcaoCapturar:
+			evento = EventoTeclado{}
+			jogoCapturar(jogo)
+		}
+		if jogo.Gravacao != nil {
+			gravacaoRegistrar(jogo.Gravacao, evento)
 		}
-		jogoProcessarEventos(&jogo)
+		if continuar := personagemExecutarAcao(evento, jogo); !continuar {
+			// Salva automaticamente ao sair para poder continuar depois
+			if jogo.ArquivoSave != "" {
+				if err := jogoSalvar(jogo, jogo.ArquivoSave); err != nil {
+					return false, textosErro("erro.save_automatico", err)
+				}
+			}
+			return false, nil
+		}
+		jogoVerificarGeradores(jogo)
+		jogoProcessarEventos(jogo)
+
+		interfaceDesenharJogo(jogo)
 
-		interfaceDesenharJogo(&jogo)
+		if jogo.FimDeJogo {
+			return false, nil
+		}
+		if jogo.NivelConcluido {
+			return true, nil
+		}
 	}
 }
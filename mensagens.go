@@ -0,0 +1,108 @@
+// mensagens.go - Registro de mensagens do jogo com horário e severidade
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Severidade de uma mensagem, da menos para a mais importante
+type Severidade int
+
+const (
+	SeveridadeDetalhe Severidade = iota // conversa dos elementos, oculta por padrão
+	SeveridadeInfo
+	SeveridadeAviso
+	SeveridadeAlerta
+)
+
+// Quantidade máxima de mensagens guardadas; as mais antigas são descartadas
+const MaxMensagens = 200
+
+type Mensagem struct {
+	Momento    time.Duration // tempo de partida em que a mensagem foi registrada
+	Severidade Severidade
+	Texto      string
+	Repeticoes int // vezes seguidas que a mesma mensagem foi registrada
+}
+
+type RegistroMensagens struct {
+	Mensagens []Mensagem
+}
+
+func registroNovo() *RegistroMensagens {
+	return &RegistroMensagens{}
+}
+
+// Acrescenta uma mensagem; repetida em seguida, só atualiza o horário e o contador
+func registroAdicionar(reg *RegistroMensagens, m Mensagem) {
+	if n := len(reg.Mensagens); n > 0 {
+		ultima := &reg.Mensagens[n-1]
+		if ultima.Texto == m.Texto && ultima.Severidade == m.Severidade {
+			ultima.Momento = m.Momento
+			ultima.Repeticoes++
+			return
+		}
+	}
+	m.Repeticoes = 1
+	reg.Mensagens = append(reg.Mensagens, m)
+	if len(reg.Mensagens) > MaxMensagens {
+		reg.Mensagens = reg.Mensagens[len(reg.Mensagens)-MaxMensagens:]
+	}
+}
+
+// Retorna as mensagens visíveis com o filtro; detalhes só aparecem se pedidos
+func registroFiltrar(reg *RegistroMensagens, detalhes bool) []Mensagem {
+	if detalhes {
+		return reg.Mensagens
+	}
+	var visiveis []Mensagem
+	for _, m := range reg.Mensagens {
+		if m.Severidade > SeveridadeDetalhe {
+			visiveis = append(visiveis, m)
+		}
+	}
+	return visiveis
+}
+
+// Registra no instante atual da partida o texto do catálogo com o identificador
+func jogoMensagem(jogo *Jogo, sev Severidade, id string, args ...interface{}) {
+	registroAdicionar(jogo.Mensagens, Mensagem{
+		Momento:    partidaTempo(jogo.Partida),
+		Severidade: sev,
+		Texto:      traduzir(id, args...),
+	})
+}
+
+// Texto de uma linha do registro, como "[01:23] Porta A aberta! (x2)"
+func mensagemFormatar(m Mensagem) string {
+	texto := fmt.Sprintf("[%s] %s", hudFormatarTempo(m.Momento), m.Texto)
+	if m.Repeticoes > 1 {
+		texto += fmt.Sprintf(" (x%d)", m.Repeticoes)
+	}
+	return texto
+}
+
+func severidadeCor(sev Severidade) Cor {
+	switch sev {
+	case SeveridadeDetalhe:
+		return CorCinzaEscuro
+	case SeveridadeAviso:
+		return CorAmarelo
+	case SeveridadeAlerta:
+		return CorVermelho
+	}
+	return CorTexto
+}
+
+func severidadeNome(sev Severidade) string {
+	switch sev {
+	case SeveridadeDetalhe:
+		return traduzir("severidade.detalhe")
+	case SeveridadeAviso:
+		return traduzir("severidade.aviso")
+	case SeveridadeAlerta:
+		return traduzir("severidade.alerta")
+	}
+	return traduzir("severidade.info")
+}
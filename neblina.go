@@ -0,0 +1,149 @@
+// neblina.go - Neblina de guerra: campo de visão do personagem e memória dos tiles já vistos
+package main
+
+// Alcance da visão do personagem, em tiles
+const RaioVisao = 8
+
+// Neblina guarda o que o personagem vê agora e o que já viu no nível
+type Neblina struct {
+	Visivel   [][]bool
+	Explorado [][]bool
+}
+
+func neblinaNova(mapa [][]Elemento) *Neblina {
+	n := &Neblina{
+		Visivel:   make([][]bool, len(mapa)),
+		Explorado: make([][]bool, len(mapa)),
+	}
+	for y, linha := range mapa {
+		n.Visivel[y] = make([]bool, len(linha))
+		n.Explorado[y] = make([]bool, len(linha))
+	}
+	return n
+}
+
+// Cria ou descarta a neblina conforme a opção e recalcula o campo de visão
+func jogoAtualizarNeblina(jogo *Jogo) {
+	if !jogo.Opcoes.Neblina {
+		jogo.Neblina = nil
+		return
+	}
+	if jogo.Neblina == nil {
+		jogo.Neblina = neblinaNova(jogo.Mapa)
+	}
+	n := jogo.Neblina
+	for y := range n.Visivel {
+		for x := range n.Visivel[y] {
+			n.Visivel[y][x] = false
+		}
+	}
+	for y := jogo.PosY - RaioVisao; y <= jogo.PosY+RaioVisao; y++ {
+		for x := jogo.PosX - RaioVisao; x <= jogo.PosX+RaioVisao; x++ {
+			dx, dy := x-jogo.PosX, y-jogo.PosY
+			if dx*dx+dy*dy > RaioVisao*RaioVisao || !neblinaDentro(n, x, y) {
+				continue
+			}
+			if jogoLinhaDeVisao(jogo, jogo.PosX, jogo.PosY, x, y) {
+				n.Visivel[y][x] = true
+				n.Explorado[y][x] = true
+			}
+		}
+	}
+}
+
+// Informa se nada bloqueia a visão entre dois pontos.
+// O tile de destino é visível mesmo que bloqueie a visão, como uma parede.
+func jogoLinhaDeVisao(jogo *Jogo, x0, y0, x1, y1 int) bool {
+	pontos := linhaEntre(x0, y0, x1, y1)
+	for i, p := range pontos {
+		// A origem e o destino não bloqueiam
+		if i > 0 && i < len(pontos)-1 && jogoBloqueiaVisao(jogo, p.X, p.Y) {
+			return false
+		}
+	}
+	return true
+}
+
+// Posições da reta entre dois pontos, incluindo os dois (algoritmo de Bresenham)
+func linhaEntre(x0, y0, x1, y1 int) []Position {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	erro := dx + dy
+	x, y := x0, y0
+	pontos := []Position{{X: x, Y: y}}
+	for x != x1 || y != y1 {
+		e2 := 2 * erro
+		if e2 >= dy {
+			erro += dy
+			x += sx
+		}
+		if e2 <= dx {
+			erro += dx
+			y += sy
+		}
+		pontos = append(pontos, Position{X: x, Y: y})
+	}
+	return pontos
+}
+
+// Paredes, portas trancadas, portões fechados e outros tiles do registro
+// marcados para isso impedem a visão
+func jogoBloqueiaVisao(jogo *Jogo, x, y int) bool {
+	elem, ok := jogoElementoEm(jogo, x, y)
+	return !ok || tileTipo(elem).BloqueiaVisao
+}
+
+func neblinaDentro(n *Neblina, x, y int) bool {
+	return y >= 0 && y < len(n.Visivel) && x >= 0 && x < len(n.Visivel[y])
+}
+
+// Informa se a posição está no campo de visão; sem neblina tudo é visível
+func jogoPosicaoVisivel(jogo *Jogo, x, y int) bool {
+	if jogo.Neblina == nil {
+		return true
+	}
+	return neblinaDentro(jogo.Neblina, x, y) && jogo.Neblina.Visivel[y][x]
+}
+
+// Informa se a posição já foi vista em algum momento do nível
+func jogoPosicaoExplorada(jogo *Jogo, x, y int) bool {
+	if jogo.Neblina == nil {
+		return true
+	}
+	return neblinaDentro(jogo.Neblina, x, y) && jogo.Neblina.Explorado[y][x]
+}
+
+// Converte os tiles explorados em linhas de texto para o save ('#' explorado, '.' não)
+func neblinaParaTexto(n *Neblina) []string {
+	var linhas []string
+	for _, linha := range n.Explorado {
+		texto := make([]byte, len(linha))
+		for x, explorado := range linha {
+			texto[x] = '.'
+			if explorado {
+				texto[x] = '#'
+			}
+		}
+		linhas = append(linhas, string(texto))
+	}
+	return linhas
+}
+
+// Restaura os tiles explorados gravados no save
+func neblinaDeTexto(mapa [][]Elemento, linhas []string) *Neblina {
+	n := neblinaNova(mapa)
+	for y, linha := range linhas {
+		for x := 0; x < len(linha); x++ {
+			if neblinaDentro(n, x, y) {
+				n.Explorado[y][x] = linha[x] == '#'
+			}
+		}
+	}
+	return n
+}
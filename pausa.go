@@ -0,0 +1,100 @@
+// pausa.go - Congelamento das goroutines dos elementos durante a pausa
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Cria o canal de pausa de um elemento. Cada elemento tem o seu, e apenas
+// o loop principal envia: true pausa e false retoma.
+func jogoNovoCanalPausa(jogo *Jogo) chan bool {
+	ch := make(chan bool, 1)
+	jogo.Pausas = append(jogo.Pausas, ch)
+	return ch
+}
+
+// Pausa ou retoma todos os elementos. Um comando ainda não lido é substituído
+// pelo novo, para que o loop principal nunca bloqueie esperando um elemento.
+func jogoPausarElementos(jogo *Jogo, pausado bool) {
+	for _, ch := range jogo.Pausas {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- pausado
+	}
+}
+
+// Bloqueia o elemento até o jogo ser retomado; retorna false se o contexto
+// for cancelado durante a pausa
+func aguardarRetomada(ctx context.Context, pausa <-chan bool) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case pausado := <-pausa:
+			if !pausado {
+				return true
+			}
+		}
+	}
+}
+
+// Congela os elementos enquanto uma tela sobreposta ao jogo está aberta.
+// A função retornada retoma o jogo; o tempo parado não conta para a partida
+// nem para a gravação. Congelamentos podem ser aninhados (salvar pelo menu de
+// pausa): só o mais externo pausa e retoma os elementos.
+func jogoCongelar(jogo *Jogo) func() {
+	jogo.Congelamentos++
+	if jogo.Congelamentos > 1 {
+		return func() { jogo.Congelamentos-- }
+	}
+	jogoPausarElementos(jogo, true)
+	inicio := time.Now()
+	return func() {
+		jogo.Congelamentos--
+		pausado := time.Since(inicio)
+		jogo.Partida.Inicio = jogo.Partida.Inicio.Add(pausado)
+		if jogo.Gravacao != nil {
+			jogo.Gravacao.inicio = jogo.Gravacao.inicio.Add(pausado)
+		}
+		jogoPausarElementos(jogo, false)
+	}
+}
+
+// Abre o menu de pausa com os elementos congelados; retorna false se o
+// jogador confirmar que quer sair da partida
+func jogoPausar(jogo *Jogo) bool {
+	retomar := jogoCongelar(jogo)
+	defer func() {
+		// Aplica as opções que possam ter sido alteradas no menu
+		jogo.Camera.ZonaMortaX = jogo.Opcoes.ZonaMortaX
+		jogo.Camera.ZonaMortaY = jogo.Opcoes.ZonaMortaY
+		retomar()
+	}()
+
+	for {
+		menu := &Menu{
+			Titulo: traduzir("pausa.titulo"),
+			Opcoes: []OpcaoMenu{
+				{Rotulo: traduzir("menu.continuar"), Resultado: "continuar"},
+				{Rotulo: traduzir("menu.salvar"), Resultado: "salvar", Visivel: func() bool { return jogo.ArquivoSave != "" }},
+				{Rotulo: traduzir("menu.opcoes"), Submenu: func() *Menu { return menuOpcoes(jogo.Opcoes) }},
+				{Rotulo: traduzir("menu.sair"), Resultado: "sair"},
+			},
+		}
+		switch interfaceNavegarMenus(menu) {
+		case "salvar":
+			jogoSalvarComAviso(jogo)
+			return true
+		case "sair":
+			if interfaceConfirmar(traduzir("pausa.confirmar_saida")) {
+				return false
+			}
+		default:
+			// Continuar ou ESC
+			return true
+		}
+	}
+}
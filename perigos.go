@@ -0,0 +1,144 @@
+// perigos.go - Armadilhas do mapa: espinhos, buracos, lama e jatos de chamas
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Evento do jato de chamas ao acender ou apagar
+const EventJatoChamas = "FlameJet"
+
+// Ciclo dos jatos de chamas
+const (
+	JatoTempoAceso   = 2 * time.Second
+	JatoTempoApagado = 3 * time.Second
+)
+
+// Tiques que o monstro fica parado ao pisar na lama
+const MonsterMudTicks = 6
+
+type JatoChamasData struct {
+	Pos   Position
+	Aceso bool
+}
+
+// Alterna o jato em pos entre aceso e apagado; quem muda o tile e aplica o dano é o jogo
+func jatoCiclo(ctx context.Context, pos Position, aceso bool, out chan<- GameEvent, pausa <-chan bool) {
+	duracao := func() time.Duration {
+		if aceso {
+			return duracaoJogo(JatoTempoAceso)
+		}
+		return duracaoJogo(JatoTempoApagado)
+	}
+	timer := time.NewTimer(duracao())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case pausado := <-pausa:
+			if pausado {
+				if !aguardarRetomada(ctx, pausa) {
+					return
+				}
+				reiniciarTimer(timer, duracao())
+			}
+		case <-timer.C:
+			aceso = !aceso
+			enviarOuDescartar(out, GameEvent{Type: EventJatoChamas, Data: JatoChamasData{Pos: pos, Aceso: aceso}})
+			timer.Reset(duracao())
+		}
+	}
+}
+
+// Cria um jato apagado em (x, y) e inicia o ciclo dele, fora da reprodução
+func jogoCriarJato(jogo *Jogo, x, y int) {
+	jogo.Mapa[y][x] = JatoApagado
+	pos := Position{X: x, Y: y}
+	jogo.Jatos = append(jogo.Jatos, pos)
+	if jogo.Reproducao != nil {
+		return
+	}
+	go jatoCiclo(jogo.Contexto, pos, false, jogo.GameEvents, jogoNovoCanalPausa(jogo))
+}
+
+// Comportamentos dos tiles de armadilha, registrados em tiles.go
+
+func personagemPisarEspinhos(jogo *Jogo, anterior Position) {
+	personagemFerir(jogo, anterior, "perigo.espinhos")
+}
+
+func personagemEntrarNasChamas(jogo *Jogo, anterior Position) {
+	personagemFerir(jogo, anterior, "perigo.chamas")
+}
+
+func personagemCairNoBuraco(jogo *Jogo, _ Position) {
+	personagemVoltarAoInicio(jogo)
+	jogoMensagem(jogo, SeveridadeAviso, "perigo.buraco")
+}
+
+func personagemAtolar(jogo *Jogo, _ Position) {
+	jogo.PresoNaLama = true
+	jogoMensagem(jogo, SeveridadeDetalhe, "perigo.lama")
+}
+
+// Tira uma vida e devolve o personagem ao tile de onde veio
+func personagemFerir(jogo *Jogo, anterior Position, mensagem string) {
+	if !personagemTirarVida(jogo, "perigo.fim") {
+		return
+	}
+	jogoMoverElemento(jogo, jogo.PosX, jogo.PosY, anterior.X-jogo.PosX, anterior.Y-jogo.PosY)
+	jogo.PosX, jogo.PosY = anterior.X, anterior.Y
+	jogoEnviarEstadoJogador(jogo)
+	jogoMensagem(jogo, SeveridadeAlerta, mensagem, jogo.Partida.Vidas)
+}
+
+// Espinhos não ferem o monstro; no buraco ou nas chamas ele volta à origem,
+// e a lama o prende por alguns tiques
+
+func jogoMonstroCairNoBuraco(jogo *Jogo) {
+	jogoDevolverMonstro(jogo)
+	jogoMensagem(jogo, SeveridadeInfo, "perigo.monstro_buraco")
+}
+
+func jogoMonstroQueimar(jogo *Jogo) {
+	jogoDevolverMonstro(jogo)
+	jogoMensagem(jogo, SeveridadeInfo, "perigo.monstro_chamas")
+}
+
+// Leva o monstro à origem e avisa a goroutine dele, que descarta a rota e o
+// destino calculados a partir do tile antigo. Se o alerta se perder com o
+// canal cheio, o monstro percebe que saiu da rota e pede outra.
+func jogoDevolverMonstro(jogo *Jogo) {
+	m := jogo.Monstro
+	m.definirPosicao(m.origin)
+	jogoAlertarMonstro(jogo, PlayerAlert{Type: "respawn"})
+}
+
+func jogoMonstroAtolar(jogo *Jogo) {
+	jogoAlertarMonstro(jogo, PlayerAlert{Type: "mud"})
+}
+
+// Acende ou apaga o jato; acendendo, atinge quem estiver sobre ele
+func jogoTratarJatoChamas(jogo *Jogo, data JatoChamasData) {
+	elem := JatoApagado
+	if data.Aceso {
+		elem = JatoAceso
+	}
+	jogoDefinirElemento(jogo, data.Pos.X, data.Pos.Y, elem)
+	if !data.Aceso {
+		return
+	}
+	if data.Pos.X == jogo.PosX && data.Pos.Y == jogo.PosY {
+		// Sem tile de onde veio, o personagem volta ao início
+		if personagemTirarVida(jogo, "perigo.fim") {
+			personagemVoltarAoInicio(jogo)
+			jogoMensagem(jogo, SeveridadeAlerta, "perigo.chamas", jogo.Partida.Vidas)
+		}
+	}
+	if jogo.Monstro != nil && jogo.Monstro.current_position == data.Pos {
+		jogoMonstroEntrarTile(jogo)
+	}
+}
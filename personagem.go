@@ -1,11 +1,6 @@
 // personagem.go - Funções para movimentação e ações do personagem
 package main
 
-import (
-	"fmt"
-	"math/rand"
-)
-
 // Atualiza a posição do personagem com base na tecla pressionada (WASD)
 func personagemMover(tecla rune, jogo *Jogo) {
 	dx, dy := 0, 0
@@ -20,11 +15,33 @@ func personagemMover(tecla rune, jogo *Jogo) {
 		dx = 1 // Move para a direita
 	}
 
+	// O personagem vira para a direção da tecla mesmo que não consiga se mover
+	if dx != 0 || dy != 0 {
+		jogo.DirX, jogo.DirY = dx, dy
+	}
+
+	// Preso na lama, o movimento só solta o personagem
+	if jogo.PresoNaLama {
+		jogo.PresoNaLama = false
+		jogoMensagem(jogo, SeveridadeDetalhe, "perigo.lama_solto")
+		return
+	}
+
+	// Andar contra um bloco o empurra uma casa; o empurrão não gasta pulo duplo
+	empurrou := false
+	if jogoEhBloco(jogo, jogo.PosX+dx, jogo.PosY+dy) {
+		if !jogoEmpurrarBloco(jogo, jogo.PosX+dx, jogo.PosY+dy, dx, dy) {
+			jogoMensagem(jogo, SeveridadeDetalhe, "bloco.preso")
+			return
+		}
+		empurrou = true
+	}
+
 	// Verificar se tem pulos duplos disponíveis
 	stepSize := 1
-	if jogo.DoubleJumps > 0 {
+	if jogo.DoubleJumps > 0 && !empurrou {
 		stepSize = 2
-		jogo.StatusMsg = fmt.Sprintf("Pulo duplo! Restam %d pulos", jogo.DoubleJumps-1)
+		jogoMensagem(jogo, SeveridadeInfo, "pulo.usado", jogo.DoubleJumps-1)
 	}
 
 	nx, ny := jogo.PosX+(dx*stepSize), jogo.PosY+(dy*stepSize)
@@ -38,74 +55,85 @@ func personagemMover(tecla rune, jogo *Jogo) {
 				if !jogoPodeMoverPara(jogo, nx, ny) {
 					return 
 				}
-				jogo.StatusMsg = fmt.Sprintf("Pulo duplo bloqueado! Restam %d pulos", jogo.DoubleJumps)
+				jogoMensagem(jogo, SeveridadeAviso, "pulo.bloqueado", jogo.DoubleJumps)
 			} else {
 				jogo.DoubleJumps--
 				if jogo.DoubleJumps == 0 {
-					jogo.StatusMsg = "Último pulo duplo usado!"
+					jogoMensagem(jogo, SeveridadeInfo, "pulo.ultimo")
 				}
 			}
 		}
 
+		anterior := Position{X: jogo.PosX, Y: jogo.PosY}
 		jogoMoverElemento(jogo, jogo.PosX, jogo.PosY, dx*stepSize, dy*stepSize)
 		jogo.PosX, jogo.PosY = nx, ny
 
-		// Verificar se coletou estrela
-		coletouEstrela := ConsumirItemEstrela(jogo)
-		if coletouEstrela {
-			jogo.DoubleJumps = 3 
-			jogo.StatusMsg = "Estrela coletada! 3 pulos duplos concedidos!"
-		}
-
-		// Verificar se coletou item de invisibilidade
-		coletouInvisibilidade := ConsumirItemInvisibilidade(jogo)
-		if coletouInvisibilidade {
-			jogo.InvisibleSteps = InvisibilityDuration
-			jogo.StatusMsg = "Invisibilidade coletada!"
-		}
+		// Coleta o item do tile (estrela e invisibilidade vão para o inventário)
+		// e aplica o comportamento dele, como saída, armadilhas e portais
+		coletou := personagemEntrarTile(jogo, anterior)
 
-		collectEvent := PlayerCollect{
-			X: jogo.PosX,
-			Y: jogo.PosY,
-		}
-		select {
-		case jogo.PlayerCollects <- collectEvent:
-		default:
+		if coletou {
+			jogoEntregarColeta(jogo, PlayerCollect{X: jogo.PosX, Y: jogo.PosY})
 		}
 
-		if !coletouInvisibilidade && !coletouEstrela && jogo.InvisibleSteps > 0 {
+		if !coletou && jogo.InvisibleSteps > 0 {
 			jogo.InvisibleSteps--
 			if jogo.InvisibleSteps == 0 {
-				jogo.StatusMsg = "Invisibilidade expirou"
+				jogoMensagem(jogo, SeveridadeAviso, "invisibilidade.expirou")
 			} else {
-				jogo.StatusMsg = fmt.Sprintf("Invisível: %d movimentos restantes", jogo.InvisibleSteps)
+				jogoMensagem(jogo, SeveridadeDetalhe, "invisibilidade.restante", jogo.InvisibleSteps)
 			}
 		}
 	} else {
 		if stepSize == 2 {
 			nx, ny = jogo.PosX+dx, jogo.PosY+dy
 			if jogoPodeMoverPara(jogo, nx, ny) {
+				anterior := Position{X: jogo.PosX, Y: jogo.PosY}
 				jogoMoverElemento(jogo, jogo.PosX, jogo.PosY, dx, dy)
 				jogo.PosX, jogo.PosY = nx, ny
-				jogo.StatusMsg = fmt.Sprintf("Pulo duplo bloqueado - movimento normal. Restam %d pulos", jogo.DoubleJumps)
+				jogoMensagem(jogo, SeveridadeAviso, "pulo.bloqueado_normal", jogo.DoubleJumps)
 
-				coletouEstrela := ConsumirItemEstrela(jogo)
-				if coletouEstrela {
-					jogo.DoubleJumps = 3
-					jogo.StatusMsg = "Estrela coletada! 3 pulos duplos concedidos!"
-				}
-
-				coletouInvisibilidade := ConsumirItemInvisibilidade(jogo)
-				if coletouInvisibilidade {
-					jogo.InvisibleSteps = InvisibilityDuration
-					jogo.StatusMsg = "Invisibilidade coletada!"
-				}
+				personagemEntrarTile(jogo, anterior)
 			}
 		}
 	}
 } 
-func personagemInteragir(jogo *Jogo) {
-	jogo.StatusMsg = fmt.Sprintf("Interagindo em (%d, %d)", jogo.PosX, jogo.PosY)
+
+// Tira uma vida do personagem pego pelo monstro; sem vidas, a partida termina
+func personagemPerderVida(jogo *Jogo) {
+	if !personagemTirarVida(jogo, "monstro.pego") {
+		return
+	}
+	personagemVoltarAoInicio(jogo)
+	jogoMensagem(jogo, SeveridadeAlerta, "monstro.pego_vidas", jogo.Partida.Vidas)
+}
+
+// Tira uma vida; na última, registra a mensagem de fim e retorna false
+func personagemTirarVida(jogo *Jogo, mensagemFim string) bool {
+	jogo.Partida.Vidas--
+	if jogo.Partida.Vidas <= 0 {
+		jogo.Partida.Vidas = 0
+		jogoMensagem(jogo, SeveridadeAlerta, mensagemFim)
+		jogo.FimDeJogo = true
+		return false
+	}
+	return true
+}
+
+// Leva o personagem de volta à posição inicial do nível
+func personagemVoltarAoInicio(jogo *Jogo) {
+	jogoMoverElemento(jogo, jogo.PosX, jogo.PosY, jogo.InicioX-jogo.PosX, jogo.InicioY-jogo.PosY)
+	jogo.PosX, jogo.PosY = jogo.InicioX, jogo.InicioY
+	jogoEnviarEstadoJogador(jogo)
+}
+
+// Marca o nível como concluído quando o personagem pisa na saída
+func personagemVerificarSaida(jogo *Jogo) {
+	if jogo.UltimoVisitado.simbolo == Saida.simbolo {
+		jogo.NivelConcluido = true
+		jogoAdicionarPontos(jogo, PontosNivelConcluido)
+		jogoMensagem(jogo, SeveridadeInfo, "nivel.concluido")
+	}
 }
 
 func personagemExecutarAcao(ev EventoTeclado, jogo *Jogo) bool {
@@ -114,12 +142,21 @@ func personagemExecutarAcao(ev EventoTeclado, jogo *Jogo) bool {
 		return false
 	case "interagir":
 		personagemInteragir(jogo)
+	case "usar_item":
+		personagemUsarItem(ev.Tecla, jogo)
+	case "salvar":
+		jogoSalvarComAviso(jogo)
+	case AcaoReiniciar:
+		jogoReiniciarQuebraCabeca(jogo)
 	case "mover":
 		personagemMover(ev.Tecla, jogo)
+		jogoVerificarColisao(jogo)
 		jogoEnviarEstadoJogador(jogo)
+		jogoAtualizarPoder(jogo)
 
-		// Envia mensagem de barulho ao monstro, tem 20% de chance de fazer barulho ao se mover
-		if rand.Float32() < 0.2 {
+		// Envia mensagem de barulho ao monstro, tem 20% de chance de fazer barulho ao se mover,
+		// menos no tile que abafa o som
+		if jogo.Rand.Float32() < 0.2*float32(1-tileTipo(jogo.UltimoVisitado).Abafamento) {
 			jogoEnviarAlerta(jogo, "noise")
 		}
 	}
@@ -132,6 +169,10 @@ func personagemExecutarAcaoComCanal(ev EventoTeclado, jogo *Jogo, playerChannel
 		return false
 	case "interagir":
 		personagemInteragir(jogo)
+	case "usar_item":
+		personagemUsarItem(ev.Tecla, jogo)
+	case "salvar":
+		jogoSalvarComAviso(jogo)
 	case "mover":
 		personagemMover(ev.Tecla, jogo)
 		playerState := PlayerState{
@@ -142,6 +183,7 @@ func personagemExecutarAcaoComCanal(ev EventoTeclado, jogo *Jogo, playerChannel
 		select {
 		case playerChannel <- playerState:
 		default:
+			descartesRegistrar("player_state")
 		}
 	}
 	return true 
@@ -0,0 +1,90 @@
+// poder.go - Poder da estrela pulsante: os monstros fogem e podem ser capturados
+package main
+
+// Duração do poder, em movimentos do personagem
+const PowerDuration = 30
+
+// Ativa o poder por alguns movimentos e avisa o monstro, que passa a fugir
+func jogoAtivarPoder(jogo *Jogo, passos int) {
+	jogo.PowerSteps = passos
+	jogoMensagem(jogo, SeveridadeInfo, "poder.ativado", passos)
+	if m := jogo.Monstro; m != nil && jogo.EstadoMonstros[m.id] != Caught {
+		jogoImporEstadoMonstro(jogo, PlayerAlert{Type: "power_start"}, Fleeing)
+	}
+	jogoEnviarRotaFuga(jogo)
+}
+
+// Gasta um movimento do poder e atualiza a rota de fuga do monstro
+func jogoAtualizarPoder(jogo *Jogo) {
+	if jogo.PowerSteps <= 0 {
+		return
+	}
+	jogo.PowerSteps--
+	if jogo.PowerSteps == 0 {
+		jogoMensagem(jogo, SeveridadeAviso, "poder.expirou")
+		if jogoMonstroAssustado(jogo) {
+			jogoImporEstadoMonstro(jogo, PlayerAlert{Type: "power_end"}, Patrolling)
+		}
+		return
+	}
+	jogoEnviarRotaFuga(jogo)
+}
+
+// Calcula a rota para o monstro se afastar do personagem e a envia a ele
+func jogoEnviarRotaFuga(jogo *Jogo) {
+	m := jogo.Monstro
+	if m == nil || jogo.EstadoMonstros[m.id] == Caught {
+		return
+	}
+	rota := caminhoFuga(jogo, m.current_position, Position{X: jogo.PosX, Y: jogo.PosY}, jogo.RecargaPortais[m.id])
+	jogoAlertarMonstro(jogo, PlayerAlert{Type: "flee", Data: rota})
+}
+
+// Informa se o monstro está fugindo do personagem
+func jogoMonstroAssustado(jogo *Jogo) bool {
+	return jogo.Monstro != nil && jogo.EstadoMonstros[jogo.Monstro.id] == Fleeing
+}
+
+// Informa se o monstro foi capturado e ainda não reapareceu
+func jogoMonstroCapturado(jogo *Jogo) bool {
+	return jogo.Monstro != nil && jogo.EstadoMonstros[jogo.Monstro.id] == Caught
+}
+
+// O personagem alcançou o monstro assustado: ganha pontos e o monstro some até reaparecer
+func jogoCapturarMonstro(jogo *Jogo) {
+	jogoImporEstadoMonstro(jogo, PlayerAlert{Type: "caught"}, Caught)
+	jogoAdicionarPontos(jogo, PontosMonstro)
+	jogoMensagem(jogo, SeveridadeInfo, "monstro.capturado", PontosMonstro)
+}
+
+// Muda o estado do monstro por decisão do jogo (poder, captura), que passa a
+// valer na hora para o jogo. O alerta não pode se perder com o canal cheio,
+// senão os dois lados discordariam até a próxima mudança; o monstro nunca
+// bloqueia ao enviar, então sempre volta a ler os alertas. A nova época faz o
+// jogo ignorar os estados que o monstro informou antes de receber o alerta.
+func jogoImporEstadoMonstro(jogo *Jogo, alert PlayerAlert, estado MonsterState) {
+	jogo.EpocaMonstro++
+	jogo.EstadoMonstros[jogo.Monstro.id] = estado
+	alert.Epoca = jogo.EpocaMonstro
+	select {
+	case jogo.PlayerAlerts <- alert:
+	case <-jogo.Contexto.Done():
+	}
+}
+
+// Elemento usado para desenhar o monstro conforme o estado dele
+func jogoElementoMonstro(jogo *Jogo) Elemento {
+	if jogoMonstroAssustado(jogo) {
+		return InimigoAssustado
+	}
+	return Inimigo
+}
+
+// Envia um alerta ao monstro sem bloquear; com o canal cheio o alerta é descartado e contado
+func jogoAlertarMonstro(jogo *Jogo, alert PlayerAlert) {
+	select {
+	case jogo.PlayerAlerts <- alert:
+	default:
+		descartesRegistrar("alert:" + alert.Type)
+	}
+}
@@ -0,0 +1,49 @@
+// pontuacao.go - Pontuação da partida, mantida entre os níveis da campanha
+package main
+
+import "time"
+
+// Pontos concedidos por cada conquista
+const (
+	PontosEstrela        = 100
+	PontosInvisibilidade = 50
+	PontosChave          = 25
+	PontosPorta          = 50
+	PontosNivelConcluido = 500
+	PontosMonstro        = 200
+)
+
+// Vidas no início da partida
+const VidasIniciais = 3
+
+// Partida guarda o que é acumulado ao longo de todos os níveis
+type Partida struct {
+	Pontos  int
+	Vidas   int
+	Inicio  time.Time
+	Semente int64 // semente da partida, da qual derivam as dos níveis
+}
+
+func partidaNova() *Partida {
+	return &Partida{Vidas: VidasIniciais, Inicio: time.Now()}
+}
+
+// Tempo decorrido desde o início da partida
+func partidaTempo(p *Partida) time.Duration {
+	return time.Since(p.Inicio)
+}
+
+func jogoAdicionarPontos(jogo *Jogo, pontos int) {
+	jogo.Partida.Pontos += pontos
+}
+
+// Pontos concedidos ao guardar um item no inventário
+func pontosDoItem(tipo string) int {
+	switch tipo {
+	case ItemPuloDuplo:
+		return PontosEstrela
+	case ItemInvisibilidade:
+		return PontosInvisibilidade
+	}
+	return 0
+}
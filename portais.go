@@ -0,0 +1,99 @@
+// portais.go - Pares de portais que levam o personagem e os monstros ao tile ligado
+package main
+
+import (
+	"sort"
+)
+
+// Movimentos da entidade, depois de atravessar, até poder usar um portal de novo
+const RecargaPortal = 3
+
+// Chave do personagem em Jogo.RecargaPortais; os monstros usam o próprio id
+const EntidadePersonagem = "personagem"
+
+// Liga os portais com o mesmo identificador. Cada identificador precisa de
+// exatamente dois portais; os pares válidos ficam ligados mesmo com erro.
+func jogoLigarPortais(jogo *Jogo) error {
+	grupos := make(map[string][]Position)
+	for y, linha := range jogo.Mapa {
+		for x := range linha {
+			if elem, _ := jogoElementoEm(jogo, x, y); elem == Portal {
+				id := jogoIdentificador(jogo, x, y)
+				grupos[id] = append(grupos[id], Position{X: x, Y: y})
+			}
+		}
+	}
+
+	jogo.Portais = make(map[Position]Position)
+	var ids []string
+	for id := range grupos {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var erro error
+	for _, id := range ids {
+		par := grupos[id]
+		if len(par) != 2 {
+			if erro == nil {
+				erro = textosErro("erro.portal_par", id, len(par))
+			}
+			continue
+		}
+		jogo.Portais[par[0]] = par[1]
+		jogo.Portais[par[1]] = par[0]
+	}
+	return erro
+}
+
+// Tile ligado ao portal em p. Os pares marcados com "@portal id personagem"
+// não levam monstros.
+func jogoPortalDestino(jogo *Jogo, p Position, monstro bool) (Position, bool) {
+	destino, ok := jogo.Portais[p]
+	if !ok || (monstro && jogo.SoPersonagem[jogoIdentificador(jogo, p.X, p.Y)]) {
+		return Position{}, false
+	}
+	return destino, true
+}
+
+// Conta um movimento da entidade na recarga dos portais
+func jogoRecarregarPortal(jogo *Jogo, entidade string) {
+	if jogo.RecargaPortais[entidade] > 0 {
+		jogo.RecargaPortais[entidade]--
+	}
+}
+
+// Se p for um portal e a recarga da entidade tiver acabado, retorna o tile
+// ligado e reinicia a recarga
+func jogoAtravessarPortal(jogo *Jogo, entidade string, p Position, monstro bool) (Position, bool) {
+	if jogo.RecargaPortais[entidade] > 0 {
+		return Position{}, false
+	}
+	destino, ok := jogoPortalDestino(jogo, p, monstro)
+	if !ok {
+		return Position{}, false
+	}
+	jogo.RecargaPortais[entidade] = RecargaPortal
+	return destino, true
+}
+
+// Leva o personagem ao par do portal onde ele entrou; o tile de saída guarda
+// o portal de origem e UltimoVisitado passa a ser o portal de destino
+func personagemAtravessarPortal(jogo *Jogo) bool {
+	destino, ok := jogoAtravessarPortal(jogo, EntidadePersonagem, Position{X: jogo.PosX, Y: jogo.PosY}, false)
+	if !ok {
+		return false
+	}
+	jogoMoverElemento(jogo, jogo.PosX, jogo.PosY, destino.X-jogo.PosX, destino.Y-jogo.PosY)
+	jogo.PosX, jogo.PosY = destino.X, destino.Y
+	jogoMensagem(jogo, SeveridadeDetalhe, "portal.atravessou", destino.X, destino.Y)
+	return true
+}
+
+// Leva o monstro ao par do portal onde ele entrou
+func jogoAtravessarPortalMonstro(jogo *Jogo) {
+	m := jogo.Monstro
+	if destino, ok := jogoAtravessarPortal(jogo, m.id, m.current_position, true); ok {
+		m.definirPosicao(destino)
+		jogoMensagem(jogo, SeveridadeDetalhe, "portal.monstro", destino.X, destino.Y)
+	}
+}
@@ -0,0 +1,160 @@
+// recordes.go - Tabela de recordes local, separada por mapa
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Versão do formato do arquivo de recordes
+const VersaoRecordes = 1
+
+// Quantidade máxima de recordes guardados por mapa
+const MaxRecordesPorMapa = 10
+
+type Recorde struct {
+	Nome    string    `json:"nome"`
+	Pontos  int       `json:"pontos"`
+	Nivel   int       `json:"nivel"` // último nível alcançado (começando em 1)
+	TempoS  int64     `json:"tempo_s"`
+	Semente int64     `json:"semente"`
+	Data    time.Time `json:"data"`
+}
+
+// TabelaRecordes guarda os recordes de um conjunto de mapas
+type TabelaRecordes struct {
+	Mapas    []string  `json:"mapas"`
+	Recordes []Recorde `json:"recordes"`
+}
+
+// ArquivoRecordes contém uma tabela por mapa, indexada pelo hash do conteúdo
+type ArquivoRecordes struct {
+	Versao  int                        `json:"versao"`
+	Tabelas map[string]*TabelaRecordes `json:"tabelas"`
+}
+
+// Caminho do arquivo de recordes no diretório de configuração do usuário
+func recordesCaminho() (string, error) {
+	dir, err := diretorioConfiguracao()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "recordes.json"), nil
+}
+
+// Calcula a chave da tabela a partir do conteúdo dos mapas, não dos nomes,
+// para que o mesmo mapa copiado ou renomeado compartilhe os recordes
+func recordesChave(mapas []string) (string, error) {
+	h := sha256.New()
+	for _, nome := range mapas {
+		dados, err := os.ReadFile(nome)
+		if err != nil {
+			return "", err
+		}
+		h.Write(dados)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// Lê o arquivo de recordes; um arquivo inexistente resulta em uma tabela vazia
+func recordesCarregar() (*ArquivoRecordes, error) {
+	arq := &ArquivoRecordes{Versao: VersaoRecordes, Tabelas: make(map[string]*TabelaRecordes)}
+	caminho, err := recordesCaminho()
+	if err != nil {
+		return nil, err
+	}
+	dados, err := os.ReadFile(caminho)
+	if os.IsNotExist(err) {
+		return arq, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(dados, arq); err != nil {
+		return nil, err
+	}
+	if arq.Tabelas == nil {
+		arq.Tabelas = make(map[string]*TabelaRecordes)
+	}
+	return arq, nil
+}
+
+func recordesSalvar(arq *ArquivoRecordes) error {
+	caminho, err := recordesCaminho()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(caminho), 0755); err != nil {
+		return err
+	}
+	dados, err := json.MarshalIndent(arq, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(caminho, dados, 0644)
+}
+
+// Insere o recorde na tabela dos mapas, mantendo apenas os melhores
+func recordesAdicionar(arq *ArquivoRecordes, chave string, mapas []string, r Recorde) {
+	tabela, ok := arq.Tabelas[chave]
+	if !ok {
+		tabela = &TabelaRecordes{Mapas: mapas}
+		arq.Tabelas[chave] = tabela
+	}
+	tabela.Recordes = append(tabela.Recordes, r)
+	sort.SliceStable(tabela.Recordes, func(i, j int) bool {
+		return tabela.Recordes[i].Pontos > tabela.Recordes[j].Pontos
+	})
+	if len(tabela.Recordes) > MaxRecordesPorMapa {
+		tabela.Recordes = tabela.Recordes[:MaxRecordesPorMapa]
+	}
+}
+
+// Retorna as chaves das tabelas em ordem estável para navegação
+func recordesChaves(arq *ArquivoRecordes) []string {
+	chaves := make([]string, 0, len(arq.Tabelas))
+	for chave := range arq.Tabelas {
+		chaves = append(chaves, chave)
+	}
+	sort.Strings(chaves)
+	return chaves
+}
+
+// Pede o nome do jogador e grava o resultado da partida
+func jogoRegistrarRecorde(jogo *Jogo) error {
+	chave, err := recordesChave(jogo.Mapas)
+	if err != nil {
+		return err
+	}
+	arq, err := recordesCarregar()
+	if err != nil {
+		return err
+	}
+
+	titulo := traduzir("recordes.fim_de_jogo")
+	if !jogo.FimDeJogo {
+		titulo = traduzir("recordes.concluida")
+	}
+	nome := interfaceLerTexto(titulo, traduzir("recordes.digite_nome", jogo.Partida.Pontos), 16)
+	if nome == "" {
+		nome = traduzir("recordes.anonimo")
+	}
+
+	recordesAdicionar(arq, chave, jogo.Mapas, Recorde{
+		Nome:    nome,
+		Pontos:  jogo.Partida.Pontos,
+		Nivel:   jogo.Nivel + 1,
+		TempoS:  int64(partidaTempo(jogo.Partida).Seconds()),
+		Semente: jogo.Partida.Semente,
+		Data:    time.Now(),
+	})
+	if err := recordesSalvar(arq); err != nil {
+		return err
+	}
+	return interfaceMostrarRecordes(arq, chave)
+}
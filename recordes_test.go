@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func recordesNomes(tabela *TabelaRecordes) []string {
+	var nomes []string
+	for _, r := range tabela.Recordes {
+		nomes = append(nomes, r.Nome)
+	}
+	return nomes
+}
+
+func TestRecordesAdicionar(t *testing.T) {
+	casos := []struct {
+		nome     string
+		pontos   []int // pontos de cada recorde inserido, nomeados a, b, c...
+		esperado []string
+	}{
+		{"ordem decrescente", []int{100, 300, 200}, []string{"b", "c", "a"}},
+		{"empate mantém o mais antigo na frente", []int{200, 300, 200}, []string{"b", "a", "c"}},
+		{"zero pontos entra no fim", []int{0, 50}, []string{"b", "a"}},
+		{
+			"só os melhores ficam",
+			[]int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 5, 55},
+			[]string{"j", "i", "h", "g", "f", "l", "e", "d", "c", "b"},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			arq := &ArquivoRecordes{Tabelas: make(map[string]*TabelaRecordes)}
+			for i, p := range c.pontos {
+				recordesAdicionar(arq, "chave", []string{"mapa.txt"}, Recorde{Nome: string(rune('a' + i)), Pontos: p})
+			}
+			if got := recordesNomes(arq.Tabelas["chave"]); !reflect.DeepEqual(got, c.esperado) {
+				t.Errorf("recordes %v, esperados %v", got, c.esperado)
+			}
+		})
+	}
+}
+
+func TestRecordesTabelasPorMapa(t *testing.T) {
+	dir := t.TempDir()
+	escrever := func(nome, conteudo string) string {
+		caminho := filepath.Join(dir, nome)
+		if err := os.WriteFile(caminho, []byte(conteudo), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return caminho
+	}
+	a := escrever("a.txt", "▤▤▤\n▤☺▤\n")
+	copia := escrever("copia.txt", "▤▤▤\n▤☺▤\n")
+	b := escrever("b.txt", "▤▤▤▤\n▤☺ ▤\n")
+
+	chave := func(mapas ...string) string {
+		c, err := recordesChave(mapas)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	if chave(a) != chave(copia) {
+		t.Error("o mesmo mapa com outro nome tem outra tabela")
+	}
+	if chave(a) == chave(b) || chave(a, b) == chave(b, a) {
+		t.Error("mapas ou campanhas diferentes dividem a tabela")
+	}
+	if _, err := recordesChave([]string{filepath.Join(dir, "inexistente.txt")}); err == nil {
+		t.Error("chave de um mapa inexistente")
+	}
+
+	// Gravado e lido de volta, cada tabela mantém os seus recordes
+	configuracoesDiretorioTeste(t)
+	arq, err := recordesCarregar()
+	if err != nil || len(arq.Tabelas) != 0 {
+		t.Fatalf("sem arquivo: %+v, %v", arq, err)
+	}
+	for i := 0; i < 3; i++ {
+		recordesAdicionar(arq, chave(a), []string{a}, Recorde{Nome: fmt.Sprint("a", i), Pontos: i})
+	}
+	recordesAdicionar(arq, chave(b), []string{b}, Recorde{Nome: "b0", Pontos: 1000, Nivel: 2})
+	if err := recordesSalvar(arq); err != nil {
+		t.Fatal(err)
+	}
+	lido, err := recordesCarregar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recordesNomes(lido.Tabelas[chave(copia)]); !reflect.DeepEqual(got, []string{"a2", "a1", "a0"}) {
+		t.Errorf("recordes de a: %v", got)
+	}
+	if tb := lido.Tabelas[chave(b)]; len(tb.Recordes) != 1 || tb.Recordes[0].Nivel != 2 || !reflect.DeepEqual(tb.Mapas, []string{b}) {
+		t.Errorf("tabela de b: %+v", tb)
+	}
+	if chaves := recordesChaves(lido); len(chaves) != 2 || chaves[0] > chaves[1] {
+		t.Errorf("chaves fora de ordem: %v", chaves)
+	}
+}
@@ -0,0 +1,345 @@
+// replay.go - Gravação das entradas do jogador e reprodução das partidas
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Versão do formato do arquivo de replay
+const VersaoReplay = 2
+
+// Multiplicador de velocidade do jogo; acelera a reprodução e os temporizadores
+// dos elementos
+var VelocidadeJogo = 1.0
+
+// Converte uma duração do jogo para tempo real, considerando a velocidade
+func duracaoJogo(d time.Duration) time.Duration {
+	if VelocidadeJogo <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) / VelocidadeJogo)
+}
+
+// EventoGravado é uma entrada do jogador com o momento em que ocorreu
+type EventoGravado struct {
+	Tick  int    `json:"tick"` // iteração do loop principal em que a entrada foi lida
+	Ms    int64  `json:"ms"`   // milissegundos desde o início da gravação
+	Tipo  string `json:"tipo"`
+	Tecla rune   `json:"tecla,omitempty"`
+}
+
+// EventoJogoGravado é um evento tratado pelo jogo, com os dados em JSON e o
+// nome do tipo Go deles, para a reprodução tratá-lo no mesmo tick
+type EventoJogoGravado struct {
+	Tick   int             `json:"tick"`
+	Ms     int64           `json:"ms"`
+	Tipo   string          `json:"tipo"`
+	Dado   string          `json:"dado,omitempty"`
+	Dados  json.RawMessage `json:"dados,omitempty"`
+	Direto bool            `json:"direto,omitempty"` // tratado na hora, com o canal de eventos cheio
+}
+
+type Gravacao struct {
+	Versao      int                 `json:"versao"`
+	Semente     int64               `json:"semente"`
+	Mapas       []string            `json:"mapas"`
+	Nivel       int                 `json:"nivel"` // nível em que a partida começou
+	Eventos     []EventoGravado     `json:"eventos"`
+	EventosJogo []EventoJogoGravado `json:"eventos_jogo"`
+	Tick        int                 `json:"ticks"` // iterações do loop principal gravadas
+	inicio      time.Time
+	erro        error // primeiro evento que não pôde ser gravado
+}
+
+// Tipos dos dados dos eventos, pelo nome gravado no replay
+var tiposDadosEvento = tiposPorNome(
+	MonsterMoveData{}, MonsterRouteData{}, MonsterStateData{}, map[string]interface{}{},
+	InvisibilityApplied{}, Invisibility{}, StarBonus{}, DoubleJumpApplied{},
+	StarCollectedData{}, StarStateChangeData{}, StarPulseData{}, StarChargedData{},
+	StarTimeoutData{}, StarCommunicationData{},
+	PortaAbertaData{}, AlavancaAcionadaData{}, ChaveColetadaData{},
+	BlocoEmpurradoData{}, GerarItemData{}, JatoChamasData{},
+)
+
+func tiposPorNome(valores ...interface{}) map[string]reflect.Type {
+	tipos := make(map[string]reflect.Type)
+	for _, v := range valores {
+		t := reflect.TypeOf(v)
+		tipos[t.String()] = t
+	}
+	return tipos
+}
+
+func gravacaoNova(semente int64, mapas []string, nivel int) *Gravacao {
+	return &Gravacao{
+		Versao:  VersaoReplay,
+		Semente: semente,
+		Mapas:   mapas,
+		Nivel:   nivel,
+		inicio:  time.Now(),
+	}
+}
+
+// Registra uma entrada; eventos vazios (teclas ignoradas) avançam o tick sem serem gravados
+func gravacaoRegistrar(g *Gravacao, ev EventoTeclado) {
+	g.Tick++
+	if ev.Tipo == "" || ev.Tipo == "redimensionar" {
+		return
+	}
+	g.Eventos = append(g.Eventos, EventoGravado{
+		Tick:  g.Tick,
+		Ms:    time.Since(g.inicio).Milliseconds(),
+		Tipo:  ev.Tipo,
+		Tecla: ev.Tecla,
+	})
+}
+
+// Registra um evento no momento em que o jogo o trata. Direto indica que ele
+// foi tratado ao ser emitido, porque o canal de eventos estava cheio.
+func gravacaoRegistrarEvento(g *Gravacao, ev GameEvent, direto bool) {
+	gravado := EventoJogoGravado{
+		Tick:   g.Tick,
+		Ms:     time.Since(g.inicio).Milliseconds(),
+		Tipo:   ev.Type,
+		Direto: direto,
+	}
+	if ev.Data != nil {
+		gravado.Dado = reflect.TypeOf(ev.Data).String()
+		dados, err := json.Marshal(ev.Data)
+		if _, conhecido := tiposDadosEvento[gravado.Dado]; !conhecido && err == nil {
+			err = textosErro("erro.replay_tipo", gravado.Dado)
+		}
+		if err != nil {
+			if g.erro == nil {
+				g.erro = textosErro("erro.replay_gravar", ev.Type, err)
+			}
+			return
+		}
+		gravado.Dados = dados
+	}
+	g.EventosJogo = append(g.EventosJogo, gravado)
+}
+
+// Reconstrói o evento com os dados no tipo em que foram gravados
+func eventoGravadoDecodificar(gravado EventoJogoGravado) (GameEvent, error) {
+	ev := GameEvent{Type: gravado.Tipo}
+	if gravado.Dado == "" {
+		return ev, nil
+	}
+	t, ok := tiposDadosEvento[gravado.Dado]
+	if !ok {
+		return ev, textosErro("erro.replay_evento", gravado.Tipo, gravado.Tick, textosErro("erro.replay_tipo", gravado.Dado))
+	}
+	dados := reflect.New(t)
+	if err := json.Unmarshal(gravado.Dados, dados.Interface()); err != nil {
+		return ev, textosErro("erro.replay_evento", gravado.Tipo, gravado.Tick, err)
+	}
+	ev.Data = dados.Elem().Interface()
+	return ev, nil
+}
+
+func gravacaoSalvar(g *Gravacao, nome string) error {
+	if g.erro != nil {
+		return g.erro
+	}
+	dados, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(nome, dados, 0644)
+}
+
+func gravacaoCarregar(nome string) (*Gravacao, error) {
+	dados, err := os.ReadFile(nome)
+	if err != nil {
+		return nil, err
+	}
+	var g Gravacao
+	if err := json.Unmarshal(dados, &g); err != nil {
+		return nil, textosErro("erro.replay_invalido", err)
+	}
+	if g.Versao != VersaoReplay {
+		return nil, textosErro("erro.replay_versao", g.Versao, VersaoReplay)
+	}
+	if g.Nivel < 0 || g.Nivel >= len(g.Mapas) {
+		return nil, textosErro("erro.replay_nivel", g.Nivel)
+	}
+	return &g, nil
+}
+
+// Reproducao é a partida gravada em reprodução. Os elementos não rodam: os
+// eventos deles vêm da gravação e são tratados nos mesmos ticks da partida
+// gravada, então o resultado é o mesmo dela.
+type Reproducao struct {
+	Gravacao *Gravacao
+	Tick     int         // último tick reproduzido
+	eventos  []GameEvent // EventosJogo decodificados
+	entrada  int         // próxima entrada a reproduzir
+	evento   int         // próximo evento a tratar
+}
+
+func reproducaoNova(g *Gravacao) (*Reproducao, error) {
+	r := &Reproducao{Gravacao: g}
+	for _, gravado := range g.EventosJogo {
+		ev, err := eventoGravadoDecodificar(gravado)
+		if err != nil {
+			return nil, err
+		}
+		r.eventos = append(r.eventos, ev)
+	}
+	return r, nil
+}
+
+// Instante do próximo tick com uma entrada ou um evento gravado (-1 se ele
+// não tiver nenhum); retorna false quando a gravação terminou
+func reproducaoEspera(r *Reproducao) (int64, bool) {
+	g := r.Gravacao
+	if r.Tick >= g.Tick {
+		return 0, false
+	}
+	if r.entrada < len(g.Eventos) && g.Eventos[r.entrada].Tick == r.Tick+1 {
+		return g.Eventos[r.entrada].Ms, true
+	}
+	if r.evento < len(g.EventosJogo) && g.EventosJogo[r.evento].Tick == r.Tick+1 {
+		return g.EventosJogo[r.evento].Ms, true
+	}
+	return -1, true
+}
+
+// Passa para o próximo tick e retorna a entrada gravada nele; um tick sem
+// entrada gravada (tecla ignorada ou pausa) reproduz uma entrada vazia
+func reproducaoAvancar(r *Reproducao) EventoTeclado {
+	r.Tick++
+	g := r.Gravacao
+	if r.entrada < len(g.Eventos) && g.Eventos[r.entrada].Tick == r.Tick {
+		ev := g.Eventos[r.entrada]
+		r.entrada++
+		return EventoTeclado{Tipo: ev.Tipo, Tecla: ev.Tecla}
+	}
+	return EventoTeclado{}
+}
+
+// Trata os eventos gravados até o tick atual. Os que foram tratados na hora
+// em que eram emitidos ficam para jogoEmitirEvento, exceto os que sobraram de
+// ticks anteriores.
+func reproducaoTratarEventos(jogo *Jogo, r *Reproducao) {
+	for r.evento < len(r.eventos) {
+		gravado := r.Gravacao.EventosJogo[r.evento]
+		if gravado.Tick > r.Tick || gravado.Direto && gravado.Tick == r.Tick {
+			return
+		}
+		reproducaoTratarProximo(jogo, r)
+	}
+}
+
+// Trata o evento emitido pelo jogo se, na gravação, ele foi tratado na hora
+// neste ponto; senão ele já está na gravação, no tick em que foi tratado
+func reproducaoTratarDireto(jogo *Jogo, r *Reproducao) {
+	if r.evento < len(r.eventos) {
+		gravado := r.Gravacao.EventosJogo[r.evento]
+		if gravado.Direto && gravado.Tick == r.Tick {
+			reproducaoTratarProximo(jogo, r)
+		}
+	}
+}
+
+func reproducaoTratarProximo(jogo *Jogo, r *Reproducao) {
+	ev := r.eventos[r.evento]
+	r.evento++
+	reproducaoAtualizarEstrela(jogo, ev)
+	jogoTratarEvento(jogo, ev)
+}
+
+// Sem a goroutine da estrela, aplica à cópia dela as mudanças que os eventos
+// informam, para que ela apareça e suma como na partida gravada
+func reproducaoAtualizarEstrela(jogo *Jogo, ev GameEvent) {
+	var star *Star
+	switch data := ev.Data.(type) {
+	case StarStateChangeData:
+		if star = jogoEstrelaPorID(jogo, data.StarID); star != nil {
+			star.State = data.NewState
+			switch data.NewState {
+			case StarVisible, StarCharging:
+				star.IsVisible = true
+			case StarInvisible:
+				star.IsVisible = false
+			}
+		}
+	case StarPulseData:
+		if star = jogoEstrelaEm(jogo, data.X, data.Y); star != nil {
+			star.IsVisible = data.IsVisible
+			star.PulseCount = data.PulseCount
+		}
+	case StarChargedData:
+		if star = jogoEstrelaEm(jogo, data.X, data.Y); star != nil {
+			star.Energy = data.Energy
+		}
+	}
+	if star != nil {
+		star.publishSnapshot()
+	}
+}
+
+// Estrela mais recente criada no tile
+func jogoEstrelaEm(jogo *Jogo, x, y int) *Star {
+	for i := len(jogo.Stars) - 1; i >= 0; i-- {
+		if jogo.Stars[i].X == x && jogo.Stars[i].Y == y {
+			return jogo.Stars[i]
+		}
+	}
+	return nil
+}
+
+// Descarta os avisos do jogo ao monstro e às estrelas, que não rodam na reprodução
+func reproducaoDescartarAvisos(ctx context.Context, jogo *Jogo) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-jogo.PlayerAlerts:
+		case <-jogo.PlayerState:
+		}
+	}
+}
+
+// Cria a fonte de entradas que reproduz a gravação tick a tick, no ritmo
+// original (dividido pela velocidade). A tecla de sair interrompe a
+// reprodução; ao final, aguarda uma tecla para sair.
+func replayEntrada(r *Reproducao, teclas MapaTeclas) func() EventoTeclado {
+	teclado := interfaceLerEventoTecladoAsync(teclas)
+	inicio := time.Now()
+
+	return func() EventoTeclado {
+		ms, ok := reproducaoEspera(r)
+		if !ok {
+			for ev := range teclado {
+				if ev.Tipo != "redimensionar" {
+					break
+				}
+			}
+			return EventoTeclado{Tipo: "sair"}
+		}
+
+		if ms >= 0 {
+			timer := time.NewTimer(time.Until(inicio.Add(duracaoJogo(time.Duration(ms) * time.Millisecond))))
+			defer timer.Stop()
+			// Um tick a mais no loop principal mudaria a partida: o redimensionamento
+			// espera o desenho do próximo tick
+			for esperando := true; esperando; {
+				select {
+				case tecla, ok := <-teclado:
+					if !ok || tecla.Tipo == "sair" {
+						return EventoTeclado{Tipo: "sair"}
+					}
+				case <-timer.C:
+					esperando = false
+				}
+			}
+		}
+		return reproducaoAvancar(r)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// O monstro fica preso no cercado, para a partida não acabar antes do roteiro
+const replayMapaTeste = `▤▤▤▤▤▤▤▤▤▤▤▤▤▤▤▤
+▤☺   ★      ▤  ▤
+▤    ¤   ♣  ▤☠ ▤
+▤  ★        ▤  ▤
+▤▤▤▤▤▤▤▤▤▤▤▤▤▤▤▤
+@gerador estrela 0.2 2
+@gerador invisibilidade 0.3 1 10,1
+`
+
+// Estado final comparado entre a partida gravada e a reproduzida. O estado
+// interno do monstro e das estrelas fica de fora: ele é das goroutines, que
+// não rodam na reprodução.
+func replayEstado(t *testing.T, jogo *Jogo) string {
+	t.Helper()
+	save := jogoParaSave(jogo)
+	save.TempoMs = 0
+	if save.Monstro != nil {
+		save.Monstro.Destino, save.Monstro.UltimaVez = Position{}, Position{}
+		save.Monstro.Estado, save.Monstro.ShiftCount = MonsterState(0), 0
+	}
+	for i := range save.Estrelas {
+		e := &save.Estrelas[i]
+		e.Estado, e.Visivel, e.Energia, e.PulseCount = StarState(0), false, 0, 0
+	}
+	var textos []string
+	for _, m := range jogo.Mensagens.Mensagens {
+		textos = append(textos, fmt.Sprintf("%s x%d", m.Texto, m.Repeticoes))
+	}
+	dados, err := json.MarshalIndent(struct {
+		Save      SaveJogo
+		Estados   map[string]MonsterState
+		Epoca     int
+		Mensagens []string
+	}{save, jogo.EstadoMonstros, jogo.EpocaMonstro, textos}, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(dados)
+}
+
+// Joga o nível com os elementos rodando e as entradas do roteiro, gravando a partida
+func replayGravarTeste(t *testing.T, mapas []string, roteiro []EventoTeclado) (*Gravacao, *Jogo) {
+	t.Helper()
+	cfg := ConfigJogo{Opcoes: configuracoesPadrao(), Semente: 7}
+	cfg.Gravacao = gravacaoNova(cfg.Semente, mapas, 0)
+	proxima := 0
+	cfg.Entrada = func() EventoTeclado {
+		if proxima >= len(roteiro) {
+			return EventoTeclado{Tipo: "sair"}
+		}
+		// Dá tempo para os elementos mandarem eventos entre as entradas. Antes
+		// de usar um item, espera o canal de eventos encher, para o evento do
+		// item ser tratado na hora.
+		espera := 20 * time.Millisecond
+		if roteiro[proxima].Tipo == "usar_item" {
+			espera = 600 * time.Millisecond
+		}
+		time.Sleep(espera)
+		proxima++
+		return roteiro[proxima-1]
+	}
+	jogo, err := jogoNovoNivel(mapas, 0, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	final, err := jogoExecutarCampanha(jogo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg.Gravacao, final
+}
+
+func TestReplayReproduzPartida(t *testing.T) {
+	anterior := tela
+	tela = NovaTelaMemoria(40, 20)
+	t.Cleanup(func() { tela = anterior })
+
+	dir := t.TempDir()
+	mapa := filepath.Join(dir, "mapa.txt")
+	if err := os.WriteFile(mapa, []byte(replayMapaTeste), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var roteiro []EventoTeclado
+	for _, tecla := range "ddddsdddaaaawwaaddddddsss" {
+		roteiro = append(roteiro, EventoTeclado{Tipo: "mover", Tecla: tecla})
+		if tecla == 'w' {
+			roteiro = append(roteiro, EventoTeclado{}, EventoTeclado{Tipo: "redimensionar"})
+		}
+	}
+	roteiro = append(roteiro, EventoTeclado{Tipo: "usar_item", Tecla: '1'}, EventoTeclado{Tipo: "usar_item", Tecla: '1'})
+	for i := 0; i < 10; i++ {
+		roteiro = append(roteiro, EventoTeclado{Tipo: "mover", Tecla: rune("wasd"[i%4])})
+	}
+
+	gravacao, gravado := replayGravarTeste(t, []string{mapa}, roteiro)
+	diretos := 0
+	for _, ev := range gravacao.EventosJogo {
+		if ev.Direto {
+			diretos++
+		}
+	}
+	if len(gravacao.EventosJogo) == 0 || diretos == 0 {
+		t.Fatalf("gravados %d eventos, %d tratados na hora; esperados dos dois tipos", len(gravacao.EventosJogo), diretos)
+	}
+	arquivo := filepath.Join(dir, "partida.json")
+	if err := gravacaoSalvar(gravacao, arquivo); err != nil {
+		t.Fatal(err)
+	}
+	carregada, err := gravacaoCarregar(arquivo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := reproducaoNova(carregada)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := ConfigJogo{Opcoes: configuracoesPadrao(), Semente: carregada.Semente, Reproducao: r}
+	cfg.Entrada = func() EventoTeclado {
+		if _, ok := reproducaoEspera(r); !ok {
+			return EventoTeclado{Tipo: "sair"}
+		}
+		return reproducaoAvancar(r)
+	}
+	jogo, err := jogoNovoNivel(carregada.Mapas, carregada.Nivel, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reproduzido, err := jogoExecutarCampanha(jogo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Tick != gravacao.Tick || r.evento != len(gravacao.EventosJogo) {
+		t.Errorf("reprodução parou no tick %d com %d eventos tratados; gravados %d ticks e %d eventos",
+			r.Tick, r.evento, gravacao.Tick, len(gravacao.EventosJogo))
+	}
+	if antes, depois := replayEstado(t, gravado), replayEstado(t, reproduzido); antes != depois {
+		t.Errorf("a reprodução divergiu da partida gravada:\ngravada:\n%s\nreproduzida:\n%s", antes, depois)
+	}
+}
+
+func TestReplayDecodificaEventos(t *testing.T) {
+	g := gravacaoNova(1, []string{"mapa.txt"}, 0)
+	eventos := []GameEvent{
+		{Type: "monster_move", Data: MonsterMoveData{OldX: 1, OldY: 2, NewX: 2, NewY: 2, MonsterID: "m"}},
+		{Type: EventGerarItem, Data: GerarItemData{Indice: 1, Geracao: 3}},
+		{Type: EventRemoveElement, Data: Invisibility{X: 4, Y: 5}},
+		{Type: "monster_collision"},
+	}
+	for _, ev := range eventos {
+		gravacaoRegistrarEvento(g, ev, false)
+	}
+	gravacaoRegistrarEvento(g, GameEvent{Type: "desconhecido", Data: struct{ A int }{1}}, false)
+	if g.erro == nil {
+		t.Error("evento com dados de tipo desconhecido gravado sem erro")
+	}
+	for i, gravado := range g.EventosJogo {
+		ev, err := eventoGravadoDecodificar(gravado)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprintf("%#v", ev) != fmt.Sprintf("%#v", eventos[i]) {
+			t.Errorf("evento %d decodificado como %#v, esperado %#v", i, ev, eventos[i])
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// save.go - Gravação e carregamento do estado do jogo em JSON
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+)
+
+// Versão do formato do arquivo de save; incrementar ao mudar a estrutura.
+// A versão 2 acrescentou vidas, poder, neblina, geradores, blocos, perigos e portais.
+const VersaoSave = 2
+
+// Versão mais antiga que ainda é carregada. Os campos que ela não tem ficam
+// vazios no JSON e são tratados como em um nível recém-começado.
+const VersaoSaveMinima = 1
+
+// Arquivo usado quando nenhum outro é informado
+const ArquivoSavePadrao = "jogo.save.json"
+
+type SaveJogo struct {
+	Versao         int              `json:"versao"`
+	Mapas          []string         `json:"mapas"`
+	Nivel          int              `json:"nivel"`
+	Semente        int64            `json:"semente"`
+	SementePartida int64            `json:"semente_partida"`
+	Pontos         int              `json:"pontos"`
+	Vidas          int              `json:"vidas"`
+	TempoMs        int64            `json:"tempo_ms"`
+	Mapa           []string         `json:"mapa"`
+	UltimoVisitado string           `json:"ultimo_visitado"`
+	PosX           int              `json:"pos_x"`
+	PosY           int              `json:"pos_y"`
+	InicioX        int              `json:"inicio_x"`
+	InicioY        int              `json:"inicio_y"`
+	DirX           int              `json:"dir_x"`
+	DirY           int              `json:"dir_y"`
+	InvisibleSteps int              `json:"invisible_steps"`
+	DoubleJumps    int              `json:"double_jumps"`
+	PowerSteps     int              `json:"power_steps,omitempty"`
+	Chaves         map[string]int   `json:"chaves"`
+	Interativos    []SaveInterativo `json:"interativos"`
+	Inventario     *Inventario      `json:"inventario"`
+	EstrelasTotal  int              `json:"estrelas_total"`
+	Coletadas      int              `json:"estrelas_coletadas"`
+	Monstro        *SaveMonstro     `json:"monstro,omitempty"`
+	Estrelas       []SaveEstrela    `json:"estrelas"`
+	Itens          []Position       `json:"itens_invisibilidade"`
+	Explorado      []string         `json:"explorado,omitempty"`
+	Geradores      []*Gerador       `json:"geradores,omitempty"`
+	BlocosIniciais []Position       `json:"blocos_iniciais,omitempty"`
+	Jatos          []Position       `json:"jatos,omitempty"`
+	PresoNaLama    bool             `json:"preso_na_lama,omitempty"`
+	SoPersonagem   map[string]bool  `json:"portais_so_personagem,omitempty"`
+	RecargaPortais map[string]int   `json:"recarga_portais,omitempty"`
+}
+
+type SaveInterativo struct {
+	X  int    `json:"x"`
+	Y  int    `json:"y"`
+	ID string `json:"id"`
+}
+
+type SaveMonstro struct {
+	ID         string       `json:"id"`
+	Posicao    Position     `json:"posicao"`
+	Destino    Position     `json:"destino"`
+	UltimaVez  Position     `json:"last_seen"`
+	Estado     MonsterState `json:"estado"`
+	ShiftCount int          `json:"shift_count"`
+	Origem     *Position    `json:"origem,omitempty"`
+}
+
+type SaveEstrela struct {
+	ID         string    `json:"id"`
+	X          int       `json:"x"`
+	Y          int       `json:"y"`
+	Estado     StarState `json:"estado"`
+	Visivel    bool      `json:"visivel"`
+	Energia    int       `json:"energia"`
+	PulseCount int       `json:"pulsos"`
+}
+
+// Converte o estado do jogo para a estrutura gravada em disco
+func jogoParaSave(jogo *Jogo) SaveJogo {
+	save := SaveJogo{
+		Versao:         VersaoSave,
+		Mapas:          jogo.Mapas,
+		Nivel:          jogo.Nivel,
+		Semente:        jogo.Semente,
+		SementePartida: jogo.Partida.Semente,
+		Pontos:         jogo.Partida.Pontos,
+		Vidas:          jogo.Partida.Vidas,
+		TempoMs:        partidaTempo(jogo.Partida).Milliseconds(),
+		UltimoVisitado: string(jogo.UltimoVisitado.simbolo),
+		PosX:           jogo.PosX,
+		PosY:           jogo.PosY,
+		InicioX:        jogo.InicioX,
+		InicioY:        jogo.InicioY,
+		DirX:           jogo.DirX,
+		DirY:           jogo.DirY,
+		InvisibleSteps: jogo.InvisibleSteps,
+		DoubleJumps:    jogo.DoubleJumps,
+		PowerSteps:     jogo.PowerSteps,
+		Chaves:         jogo.Chaves,
+		Inventario:     jogo.Inventario,
+		EstrelasTotal:  jogo.EstrelasTotal,
+		Coletadas:      jogo.EstrelasColetadas,
+	}
+
+	for _, linha := range jogo.Mapa {
+		simbolos := make([]rune, len(linha))
+		for x, e := range linha {
+			simbolos[x] = e.simbolo
+		}
+		save.Mapa = append(save.Mapa, string(simbolos))
+	}
+
+	for pos, it := range jogo.Interativos {
+		save.Interativos = append(save.Interativos, SaveInterativo{X: pos.X, Y: pos.Y, ID: it.ID})
+	}
+	// Em ordem de leitura do mapa, para o mesmo estado gerar sempre o mesmo arquivo
+	sort.Slice(save.Interativos, func(i, j int) bool {
+		a, b := save.Interativos[i], save.Interativos[j]
+		return a.Y < b.Y || a.Y == b.Y && a.X < b.X
+	})
+
+	// O estado interno das entidades vem da cópia que as goroutines publicam,
+	// para o save não pegar uma iteração delas pela metade
+	if m := jogo.Monstro; m != nil {
+		s := m.snapshot()
+		save.Monstro = &SaveMonstro{
+			ID:         m.id,
+			Posicao:    m.current_position,
+			Destino:    s.Destino,
+			UltimaVez:  s.UltimaVez,
+			Estado:     s.Estado,
+			ShiftCount: s.ShiftCount,
+			Origem:     &m.origin,
+		}
+	}
+
+	for _, star := range jogo.Stars {
+		s := star.snapshot()
+		save.Estrelas = append(save.Estrelas, SaveEstrela{
+			ID:         star.ID,
+			X:          star.X,
+			Y:          star.Y,
+			Estado:     s.State,
+			Visivel:    s.IsVisible,
+			Energia:    s.Energy,
+			PulseCount: s.PulseCount,
+		})
+	}
+
+	if jogo.Neblina != nil {
+		save.Explorado = neblinaParaTexto(jogo.Neblina)
+	}
+	save.Geradores = jogo.Geradores
+	save.BlocosIniciais = jogo.BlocosIniciais
+	save.Jatos = jogo.Jatos
+	save.PresoNaLama = jogo.PresoNaLama
+	save.SoPersonagem = jogo.SoPersonagem
+	save.RecargaPortais = jogo.RecargaPortais
+
+	// Apenas itens que ainda estão no mapa
+	for _, item := range jogo.InvisibilityItems {
+		if elem, ok := jogoElementoEm(jogo, item.X, item.Y); ok && elem.simbolo == InvisibilityItem.simbolo {
+			save.Itens = append(save.Itens, Position{X: item.X, Y: item.Y})
+		}
+	}
+	return save
+}
+
+// Reconstrói o estado do jogo a partir de um save
+func jogoDeSave(save SaveJogo, jogo *Jogo) error {
+	if save.Versao < VersaoSaveMinima || save.Versao > VersaoSave {
+		return textosErro("erro.save_versao", save.Versao, VersaoSaveMinima, VersaoSave)
+	}
+
+	jogo.Mapas = save.Mapas
+	jogo.Nivel = save.Nivel
+	jogoDefinirSemente(jogo, save.Semente)
+	jogo.Partida = &Partida{
+		Pontos:  save.Pontos,
+		Vidas:   save.Vidas,
+		Inicio:  time.Now().Add(-time.Duration(save.TempoMs) * time.Millisecond),
+		Semente: save.SementePartida,
+	}
+	if save.Versao < 2 && jogo.Partida.Vidas <= 0 {
+		jogo.Partida.Vidas = VidasIniciais // saves anteriores às vidas
+	}
+	jogo.Mapa = nil
+	for _, linha := range save.Mapa {
+		var elems []Elemento
+		for _, ch := range linha {
+			elems = append(elems, jogoElementoPorSimbolo(ch))
+		}
+		jogo.Mapa = append(jogo.Mapa, elems)
+	}
+	for _, ch := range save.UltimoVisitado {
+		jogo.UltimoVisitado = jogoElementoPorSimbolo(ch)
+	}
+	jogo.PosX, jogo.PosY = save.PosX, save.PosY
+	jogo.InicioX, jogo.InicioY = save.InicioX, save.InicioY
+	jogo.DirX, jogo.DirY = save.DirX, save.DirY
+	jogo.InvisibleSteps = save.InvisibleSteps
+	jogo.DoubleJumps = save.DoubleJumps
+	jogo.PowerSteps = save.PowerSteps
+	jogo.EstrelasTotal = save.EstrelasTotal
+	jogo.EstrelasColetadas = save.Coletadas
+	if save.Chaves != nil {
+		jogo.Chaves = save.Chaves
+	}
+	for _, it := range save.Interativos {
+		jogo.Interativos[Position{X: it.X, Y: it.Y}] = &Interativo{ID: it.ID}
+	}
+	if save.Inventario != nil {
+		jogo.Inventario = save.Inventario
+	}
+
+	if m := save.Monstro; m != nil {
+		jogo.Monstro = &Monster{
+			current_position: m.Posicao,
+			destiny_position: m.Destino,
+			last_seen:        m.UltimaVez,
+			state:            m.Estado,
+			shift_count:      m.ShiftCount,
+			id:               m.ID,
+			rng:              rand.New(rand.NewSource(save.Semente)),
+			origin:           m.Posicao,
+		}
+		// Saves antigos não guardam a origem
+		if m.Origem != nil {
+			jogo.Monstro.origin = *m.Origem
+		}
+		jogo.Monstro.publishSnapshot()
+	}
+
+	for _, e := range save.Estrelas {
+		star := NewStar(e.X, e.Y, e.ID)
+		star.State = e.Estado
+		star.IsVisible = e.Visivel
+		star.Energy = e.Energia
+		star.PulseCount = e.PulseCount
+		star.publishSnapshot()
+		jogo.Stars = append(jogo.Stars, star)
+	}
+
+	for _, pos := range save.Itens {
+		jogo.InvisibilityItems = append(jogo.InvisibilityItems, &Invisibility{X: pos.X, Y: pos.Y})
+	}
+	jogo.Geradores = save.Geradores
+	jogo.BlocosIniciais = save.BlocosIniciais
+	jogo.Jatos = save.Jatos
+	jogo.PresoNaLama = save.PresoNaLama
+	if save.SoPersonagem != nil {
+		jogo.SoPersonagem = save.SoPersonagem
+	}
+	if save.RecargaPortais != nil {
+		jogo.RecargaPortais = save.RecargaPortais
+	}
+	if err := jogoLigarPortais(jogo); err != nil {
+		return err
+	}
+	if save.Explorado != nil {
+		jogo.Neblina = neblinaDeTexto(jogo.Mapa, save.Explorado)
+	}
+	return nil
+}
+
+// Grava o estado atual do jogo no arquivo. Os elementos ficam congelados
+// enquanto o estado deles é lido, e o de cada um vem da cópia publicada por ele.
+func jogoSalvar(jogo *Jogo, nome string) error {
+	retomar := jogoCongelar(jogo)
+	defer retomar()
+	dados, err := json.MarshalIndent(jogoParaSave(jogo), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(nome, dados, 0644)
+}
+
+// Carrega um jogo salvo do arquivo
+func jogoCarregarSave(nome string, jogo *Jogo) error {
+	dados, err := os.ReadFile(nome)
+	if err != nil {
+		return err
+	}
+	var save SaveJogo
+	if err := json.Unmarshal(dados, &save); err != nil {
+		return textosErro("erro.save_invalido", err)
+	}
+	return jogoDeSave(save, jogo)
+}
+
+// Salva o jogo no arquivo configurado e informa o resultado na barra de status
+func jogoSalvarComAviso(jogo *Jogo) {
+	if jogo.ArquivoSave == "" {
+		jogoMensagem(jogo, SeveridadeAviso, "save.desativado")
+		return
+	}
+	if err := jogoSalvar(jogo, jogo.ArquivoSave); err != nil {
+		jogoMensagem(jogo, SeveridadeAlerta, "save.erro", err)
+		return
+	}
+	jogoMensagem(jogo, SeveridadeInfo, "save.salvo", jogo.ArquivoSave)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Monta um nível com um exemplo de cada parte do estado que o save guarda
+func saveJogoTeste() *Jogo {
+	jogo := telaJogoTeste(20, 10, 3, 3)
+	jogo.Mapas = []string{"teste.txt"}
+	jogo.ArquivoSave = ""
+	jogo.Mapa[2][5] = Chave
+	jogo.Interativos[Position{X: 5, Y: 2}] = &Interativo{ID: "azul"}
+	jogo.Mapa[4][8] = PortaTrancada
+	jogo.Interativos[Position{X: 8, Y: 4}] = &Interativo{ID: "azul"}
+	jogo.Chaves["vermelha"] = 1
+	inventarioAdicionar(jogo.Inventario, ItemInvisibilidade)
+	inventarioAdicionar(jogo.Inventario, ItemPuloDuplo)
+	inventarioAdicionar(jogo.Inventario, ItemPuloDuplo)
+
+	jogoCarregarMonstro(jogo, 10, 5, Inimigo)
+	jogo.Mapa[6][12] = StarElementVisible
+	jogo.Stars = append(jogo.Stars, NewStar(12, 6, "star_1"))
+	jogo.Mapa[7][2] = InvisibilityItem
+	jogo.InvisibilityItems = append(jogo.InvisibilityItems, &Invisibility{X: 2, Y: 7})
+	jogo.Geradores = []*Gerador{{Item: GeradorEstrela, Recarga: 2 * time.Second, Maximo: 2, Posicoes: []Position{{X: 14, Y: 2}}}}
+	jogo.Mapa[3][15] = JatoApagado
+	jogo.Jatos = []Position{{X: 15, Y: 3}}
+
+	jogo.UltimoVisitado = Lama
+	jogo.PresoNaLama = true
+	jogo.InvisibleSteps = 4
+	jogo.DoubleJumps = 2
+	jogo.PowerSteps = 7
+	jogo.Partida.Pontos = 350
+	jogo.Partida.Vidas = 2
+	jogo.RecargaPortais[EntidadePersonagem] = 2
+	return jogo
+}
+
+// Estado gravado, sem o tempo de partida, que continua correndo
+func saveTexto(t *testing.T, jogo *Jogo) string {
+	t.Helper()
+	save := jogoParaSave(jogo)
+	save.TempoMs = 0
+	dados, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(dados)
+}
+
+func TestSaveIdaEVolta(t *testing.T) {
+	jogo := saveJogoTeste()
+	nome := filepath.Join(t.TempDir(), "save.json")
+	if err := jogoSalvar(jogo, nome); err != nil {
+		t.Fatal(err)
+	}
+	carregado := jogoNovo()
+	if err := jogoCarregarSave(nome, &carregado); err != nil {
+		t.Fatal(err)
+	}
+	if antes, depois := saveTexto(t, jogo), saveTexto(t, &carregado); antes != depois {
+		t.Errorf("estado diferente depois de carregar:\nsalvo:\n%s\ncarregado:\n%s", antes, depois)
+	}
+	if carregado.Monstro == nil || carregado.Monstro.origin != (Position{X: 10, Y: 5}) {
+		t.Errorf("monstro carregado %+v, esperada a origem (10, 5)", carregado.Monstro)
+	}
+	if jogoElementoPorSimbolo(carregado.UltimoVisitado.simbolo) != Lama {
+		t.Errorf("UltimoVisitado = %q, esperada a lama", carregado.UltimoVisitado.simbolo)
+	}
+}
+
+// Um save da versão 1 não tem vidas: o jogo as repõe em vez de terminar a partida
+func TestSaveVersaoAntiga(t *testing.T) {
+	jogo := saveJogoTeste()
+	save := jogoParaSave(jogo)
+	save.Versao = 1
+	save.Vidas = 0
+	carregado := jogoNovo()
+	if err := jogoDeSave(save, &carregado); err != nil {
+		t.Fatal(err)
+	}
+	if carregado.Partida.Vidas != VidasIniciais {
+		t.Errorf("vidas = %d, esperado %d", carregado.Partida.Vidas, VidasIniciais)
+	}
+
+	save.Versao = VersaoSave + 1
+	if err := jogoDeSave(save, &carregado); err == nil {
+		t.Error("save de versão futura aceito")
+	}
+}
+
+// O monstro e a estrela continuam rodando enquanto o jogo salva; cada save
+// usa a cópia publicada por eles. Com -race, confere que não há disputa.
+func TestSaveComElementosRodando(t *testing.T) {
+	jogo := saveJogoTeste()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jogo.Contexto = ctx
+	go jogo.Monstro.Run(ctx, jogo.GameEvents, jogo.PlayerAlerts, jogo.PlayerState, jogoNovoCanalPausa(jogo))
+	for _, star := range jogo.Stars {
+		jogoIniciarEstrela(jogo, star)
+	}
+
+	nome := filepath.Join(t.TempDir(), "save.json")
+	for i := 0; i < 20; i++ {
+		jogoAlertarMonstro(jogo, PlayerAlert{Type: "noise", Data: map[string]int{"x": 4 + i%8, "y": 5}})
+		jogoEnviarComandoEstrela(jogo, StarCommand{Type: "pulse"})
+		if err := jogoSalvar(jogo, nome); err != nil {
+			t.Fatal(err)
+		}
+		carregado := jogoNovo()
+		if err := jogoCarregarSave(nome, &carregado); err != nil {
+			t.Fatal(err)
+		}
+		if carregado.Monstro == nil || len(carregado.Stars) != 1 {
+			t.Fatalf("save sem o monstro ou a estrela: %+v", carregado)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
@@ -0,0 +1,282 @@
+// teclas.go - Mapeamento de teclas físicas para as ações do jogador
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Ações que podem ser associadas a teclas. As ações de movimento e de uso de
+// item viram eventos "mover" e "usar_item"; as demais usam o próprio nome como tipo.
+const (
+	AcaoCima        = "cima"
+	AcaoBaixo       = "baixo"
+	AcaoEsquerda    = "esquerda"
+	AcaoDireita     = "direita"
+	AcaoInteragir   = "interagir"
+	AcaoSalvar      = "salvar"
+	AcaoMensagens   = "mensagens"
+	AcaoPausar      = "pausar"
+	AcaoDepurar     = "depurar"
+	AcaoConsole     = "console"
+	AcaoReiniciar   = "reiniciar"
+	AcaoCapturar    = "capturar"
+	PrefixoAcaoItem = "item" // item1 a item9
+)
+
+// MapaTeclas associa cada ação às teclas que a disparam
+type MapaTeclas map[string][]string
+
+// Ações na ordem em que aparecem na tela de teclas; em caso de conflito,
+// a primeira ação da lista fica com a tecla
+func teclasAcoes() []string {
+	acoes := []string{AcaoCima, AcaoBaixo, AcaoEsquerda, AcaoDireita, AcaoInteragir, AcaoSalvar, AcaoMensagens, AcaoPausar, AcaoReiniciar, AcaoDepurar, AcaoConsole, AcaoCapturar}
+	for i := 1; i <= 9; i++ {
+		acoes = append(acoes, fmt.Sprintf("%s%d", PrefixoAcaoItem, i))
+	}
+	return acoes
+}
+
+// Teclas padrão. No termbox o teclado numérico chega como dígitos (num lock
+// ligado) ou como setas e teclas de navegação (desligado); com num lock, os
+// dígitos do teclado numérico usam os itens como os da fileira de cima.
+func teclasPadrao() MapaTeclas {
+	m := MapaTeclas{
+		AcaoCima:      {"w", "seta_cima"},
+		AcaoBaixo:     {"s", "seta_baixo"},
+		AcaoEsquerda:  {"a", "seta_esquerda"},
+		AcaoDireita:   {"d", "seta_direita"},
+		AcaoInteragir: {"e", "enter"},
+		AcaoSalvar:    {"g"},
+		AcaoMensagens: {"l"},
+		AcaoPausar:    {"esc", "p"},
+		AcaoDepurar:   {"f3"},
+		AcaoConsole:   {"f2"},
+		AcaoReiniciar: {"r"},
+		AcaoCapturar:  {"f5"},
+	}
+	for i := 1; i <= 9; i++ {
+		m[fmt.Sprintf("%s%d", PrefixoAcaoItem, i)] = []string{fmt.Sprint(i)}
+	}
+	return m
+}
+
+// Nomes das teclas especiais usados no arquivo de configuração
+var teclasEspeciais = map[termbox.Key]string{
+	termbox.KeyArrowUp:    "seta_cima",
+	termbox.KeyArrowDown:  "seta_baixo",
+	termbox.KeyArrowLeft:  "seta_esquerda",
+	termbox.KeyArrowRight: "seta_direita",
+	termbox.KeyEsc:        "esc",
+	termbox.KeyEnter:      "enter",
+	termbox.KeySpace:      "espaco",
+	termbox.KeyTab:        "tab",
+	termbox.KeyBackspace2: "backspace",
+	termbox.KeyHome:       "home",
+	termbox.KeyEnd:        "end",
+	termbox.KeyPgup:       "pgup",
+	termbox.KeyPgdn:       "pgdn",
+	termbox.KeyInsert:     "insert",
+	termbox.KeyDelete:     "delete",
+	termbox.KeyF1:         "f1",
+	termbox.KeyF2:         "f2",
+	termbox.KeyF3:         "f3",
+	termbox.KeyF4:         "f4",
+	termbox.KeyF5:         "f5",
+	termbox.KeyF6:         "f6",
+	termbox.KeyF7:         "f7",
+	termbox.KeyF8:         "f8",
+	termbox.KeyF9:         "f9",
+	termbox.KeyF10:        "f10",
+	termbox.KeyF11:        "f11",
+	termbox.KeyF12:        "f12",
+}
+
+// Símbolos exibidos no lugar dos nomes de algumas teclas especiais
+var teclasRotulos = map[string]string{
+	"seta_cima":     "↑",
+	"seta_baixo":    "↓",
+	"seta_esquerda": "←",
+	"seta_direita":  "→",
+	"esc":           "ESC",
+	"enter":         "Enter",
+}
+
+// Prefixo dos nomes das teclas do teclado numérico (num0 a num9)
+const PrefixoTeclaNumerica = "num"
+
+// Tecla que o terminal de fato entrega para um nome. Com num lock o teclado
+// numérico chega como os dígitos da fileira de cima, então "num8" e "8" são a
+// mesma tecla e não podem disparar ações diferentes.
+func teclaFisica(nome string) string {
+	if len(nome) == len(PrefixoTeclaNumerica)+1 && strings.HasPrefix(nome, PrefixoTeclaNumerica) && unicode.IsDigit(rune(nome[len(nome)-1])) {
+		return nome[len(PrefixoTeclaNumerica):]
+	}
+	return nome
+}
+
+// Nome da tecla física de um evento do termbox; letras não diferenciam maiúsculas
+func teclaNome(ev termbox.Event) string {
+	if nome, ok := teclasEspeciais[ev.Key]; ok {
+		return nome
+	}
+	if ev.Ch != 0 {
+		return string(unicode.ToLower(ev.Ch))
+	}
+	return ""
+}
+
+// Texto curto de uma tecla para a interface
+func teclaRotulo(nome string) string {
+	if nome == "espaco" {
+		return traduzir("tecla.espaco")
+	}
+	if rotulo, ok := teclasRotulos[nome]; ok {
+		return rotulo
+	}
+	if fisica := teclaFisica(nome); fisica != nome {
+		return traduzir("tecla.numerica", fisica)
+	}
+	return strings.ToUpper(nome)
+}
+
+// Índice inverso tecla -> ação, respeitando a ordem de prioridade das ações
+func teclasIndice(m MapaTeclas) map[string]string {
+	indice := make(map[string]string)
+	for _, acao := range teclasAcoes() {
+		for _, tecla := range m[acao] {
+			if _, ok := indice[teclaFisica(tecla)]; !ok {
+				indice[teclaFisica(tecla)] = acao
+			}
+		}
+	}
+	return indice
+}
+
+// Teclas associadas a mais de uma ação, com as ações em conflito. As teclas
+// do teclado numérico contam como os dígitos que o terminal entrega.
+func teclasConflitos(m MapaTeclas) map[string][]string {
+	usos := make(map[string][]string)
+	for _, acao := range teclasAcoes() {
+		for _, tecla := range m[acao] {
+			fisica := teclaFisica(tecla)
+			if !teclasContem(usos[fisica], acao) {
+				usos[fisica] = append(usos[fisica], acao)
+			}
+		}
+	}
+	for tecla, acoes := range usos {
+		if len(acoes) < 2 {
+			delete(usos, tecla)
+		}
+	}
+	return usos
+}
+
+// Conflitos de teclas descritos no idioma de quando são exibidos
+type ConflitosTeclas map[string][]string
+
+func (c ConflitosTeclas) String() string {
+	var partes []string
+	for tecla, acoes := range c {
+		var nomes []string
+		for _, acao := range acoes {
+			nomes = append(nomes, acaoRotulo(acao))
+		}
+		partes = append(partes, traduzir("teclas.conflito", teclaRotulo(tecla), strings.Join(nomes, ", ")))
+	}
+	sort.Strings(partes)
+	return strings.Join(partes, "; ")
+}
+
+// Retorna um erro descrevendo os conflitos, se houver
+func teclasValidar(m MapaTeclas) error {
+	conflitos := teclasConflitos(m)
+	if len(conflitos) == 0 {
+		return nil
+	}
+	return textosErro("teclas.conflitos", ConflitosTeclas(conflitos))
+}
+
+// Associa a tecla à ação, retirando-a de qualquer outra ação que a usava.
+// Retorna a ação que perdeu a tecla ("" se nenhuma).
+func teclasAssociar(m MapaTeclas, acao, tecla string, substituir bool) string {
+	anterior := ""
+	for outra, teclas := range m {
+		for i, t := range teclas {
+			if teclaFisica(t) == teclaFisica(tecla) && outra != acao {
+				m[outra] = append(teclas[:i:i], teclas[i+1:]...)
+				anterior = outra
+				break
+			}
+		}
+	}
+	if substituir {
+		m[acao] = []string{tecla}
+	} else if !teclasContem(m[acao], tecla) {
+		m[acao] = append(m[acao], tecla)
+	}
+	return anterior
+}
+
+func teclasContem(teclas []string, tecla string) bool {
+	for _, t := range teclas {
+		if t == tecla {
+			return true
+		}
+	}
+	return false
+}
+
+// Converte a ação associada a uma tecla no evento entendido pelo jogo
+func teclasEvento(acao string) EventoTeclado {
+	switch acao {
+	case AcaoCima:
+		return EventoTeclado{Tipo: "mover", Tecla: 'w'}
+	case AcaoBaixo:
+		return EventoTeclado{Tipo: "mover", Tecla: 's'}
+	case AcaoEsquerda:
+		return EventoTeclado{Tipo: "mover", Tecla: 'a'}
+	case AcaoDireita:
+		return EventoTeclado{Tipo: "mover", Tecla: 'd'}
+	case "":
+		return EventoTeclado{}
+	}
+	var n int
+	if _, err := fmt.Sscanf(acao, PrefixoAcaoItem+"%d", &n); err == nil && n >= 1 && n <= 9 {
+		return EventoTeclado{Tipo: "usar_item", Tecla: rune('0' + n)}
+	}
+	return EventoTeclado{Tipo: acao}
+}
+
+// Traduz um evento do termbox usando o mapa de teclas; teclas sem ação
+// resultam em um evento vazio
+func teclasTraduzir(ev termbox.Event, m MapaTeclas) EventoTeclado {
+	if ev.Type == termbox.EventResize {
+		return EventoTeclado{Tipo: "redimensionar"}
+	}
+	if ev.Type != termbox.EventKey {
+		return EventoTeclado{}
+	}
+	return teclasEvento(teclasIndice(m)[teclaNome(ev)])
+}
+
+// Rótulo da primeira tecla de uma ação, para os textos de ajuda
+func teclasPrincipal(m MapaTeclas, acao string) string {
+	if len(m[acao]) == 0 {
+		return "-"
+	}
+	return teclaRotulo(m[acao][0])
+}
+
+// Nome de uma ação para a tela de teclas
+func acaoRotulo(acao string) string {
+	if strings.HasPrefix(acao, PrefixoAcaoItem) {
+		return traduzir("acao.item", strings.TrimPrefix(acao, PrefixoAcaoItem))
+	}
+	return traduzir("acao." + acao)
+}
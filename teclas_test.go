@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTeclasConflitos(t *testing.T) {
+	casos := []struct {
+		nome     string
+		mudar    func(m MapaTeclas)
+		esperado map[string][]string
+	}{
+		{"padrão", func(MapaTeclas) {}, map[string][]string{}},
+		{"mesma tecla em duas ações", func(m MapaTeclas) {
+			m[AcaoSalvar] = []string{"g", "w"}
+		}, map[string][]string{"w": {AcaoCima, AcaoSalvar}}},
+		{"teclado numérico conta como dígito", func(m MapaTeclas) {
+			m[AcaoInteragir] = append(m[AcaoInteragir], "num1")
+		}, map[string][]string{"1": {AcaoInteragir, PrefixoAcaoItem + "1"}}},
+		{"repetida na mesma ação", func(m MapaTeclas) {
+			m[AcaoPausar] = []string{"p", "p", "esc"}
+		}, map[string][]string{}},
+		{"três ações", func(m MapaTeclas) {
+			m[AcaoMensagens] = []string{"e"}
+			m[AcaoReiniciar] = []string{"e"}
+		}, map[string][]string{"e": {AcaoInteragir, AcaoMensagens, AcaoReiniciar}}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			m := teclasPadrao()
+			c.mudar(m)
+			if got := teclasConflitos(m); !reflect.DeepEqual(got, c.esperado) {
+				t.Errorf("conflitos = %v, esperados %v", got, c.esperado)
+			}
+			if err := teclasValidar(m); (err != nil) != (len(c.esperado) > 0) {
+				t.Errorf("teclasValidar = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// tela.go - Abstração da tela usada pela renderização (termbox ou memória)
+package main
+
+import (
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Tela é o destino de desenho da interface
+type Tela interface {
+	Tamanho() (int, int)
+	DefinirCelula(x, y int, ch rune, cor, corFundo Cor)
+	Limpar()
+	Atualizar()
+}
+
+// Tela atualmente em uso pela interface
+var tela Tela = telaTermbox{}
+
+// Implementação da tela sobre o termbox
+type telaTermbox struct{}
+
+func (telaTermbox) Tamanho() (int, int) {
+	return termbox.Size()
+}
+
+func (telaTermbox) DefinirCelula(x, y int, ch rune, cor, corFundo Cor) {
+	termbox.SetCell(x, y, ch, cor, corFundo)
+}
+
+func (telaTermbox) Limpar() {
+	termbox.Clear(CorPadrao, CorPadrao)
+}
+
+func (telaTermbox) Atualizar() {
+	termbox.Flush()
+}
+
+// Celula guarda o conteúdo de uma posição da tela em memória
+type Celula struct {
+	Ch       rune
+	Cor      Cor
+	CorFundo Cor
+}
+
+// TelaMemoria é uma tela sem terminal, usada para renderizar sem termbox
+type TelaMemoria struct {
+	Largura, Altura int
+	Celulas         [][]Celula
+	Quadros         int // número de vezes que a tela foi atualizada
+}
+
+func NovaTelaMemoria(largura, altura int) *TelaMemoria {
+	t := &TelaMemoria{}
+	t.Redimensionar(largura, altura)
+	return t
+}
+
+// Altera o tamanho da tela, descartando o conteúdo atual
+func (t *TelaMemoria) Redimensionar(largura, altura int) {
+	t.Largura, t.Altura = largura, altura
+	t.Celulas = make([][]Celula, altura)
+	for y := range t.Celulas {
+		t.Celulas[y] = make([]Celula, largura)
+	}
+	t.Limpar()
+}
+
+func (t *TelaMemoria) Tamanho() (int, int) {
+	return t.Largura, t.Altura
+}
+
+func (t *TelaMemoria) DefinirCelula(x, y int, ch rune, cor, corFundo Cor) {
+	if x < 0 || y < 0 || x >= t.Largura || y >= t.Altura {
+		return
+	}
+	t.Celulas[y][x] = Celula{ch, cor, corFundo}
+}
+
+func (t *TelaMemoria) Limpar() {
+	for y := range t.Celulas {
+		for x := range t.Celulas[y] {
+			t.Celulas[y][x] = Celula{' ', CorPadrao, CorPadrao}
+		}
+	}
+}
+
+func (t *TelaMemoria) Atualizar() {
+	t.Quadros++
+}
+
+// Retorna o texto de uma linha da tela, sem espaços à direita
+func (t *TelaMemoria) Linha(y int) string {
+	if y < 0 || y >= t.Altura {
+		return ""
+	}
+	var sb strings.Builder
+	for _, c := range t.Celulas[y] {
+		sb.WriteRune(c.Ch)
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// Retorna todo o conteúdo da tela como texto
+func (t *TelaMemoria) String() string {
+	linhas := make([]string, t.Altura)
+	for y := range linhas {
+		linhas[y] = t.Linha(y)
+	}
+	return strings.Join(linhas, "\n")
+}
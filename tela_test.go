@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Monta um jogo com um mapa cercado por paredes e o personagem em (x, y)
+func telaJogoTeste(largura, altura, x, y int) *Jogo {
+	jogo := jogoNovo()
+	for i := 0; i < altura; i++ {
+		linha := make([]Elemento, largura)
+		for j := range linha {
+			if i == 0 || j == 0 || i == altura-1 || j == largura-1 {
+				linha[j] = Parede
+			} else {
+				linha[j] = Vazio
+			}
+		}
+		jogo.Mapa = append(jogo.Mapa, linha)
+	}
+	jogo.PosX, jogo.PosY = x, y
+	return &jogo
+}
+
+// Desenha o jogo em uma tela em memória do tamanho pedido
+func telaDesenharTeste(t *testing.T, jogo *Jogo, largura, altura int) *TelaMemoria {
+	t.Helper()
+	mem := NovaTelaMemoria(largura, altura)
+	anterior := tela
+	tela = mem
+	t.Cleanup(func() { tela = anterior })
+	interfaceDesenharJogo(jogo)
+	return mem
+}
+
+func TestTelaPequena(t *testing.T) {
+	aviso := traduzir("tela.pequena")
+	casos := []struct {
+		nome            string
+		largura, altura int
+	}{
+		{"estreita", LarguraMinimaTela - 1, 30},
+		{"baixa", 80, AlturaMinimaTela - 1},
+		{"minúscula", 5, 3},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			jogo := telaJogoTeste(60, 30, 5, 5)
+			mem := telaDesenharTeste(t, jogo, c.largura, c.altura)
+			saida := mem.String()
+			// Em telas muito estreitas o aviso é cortado, mas o início aparece
+			inicio := string([]rune(aviso)[:min(len([]rune(aviso)), c.largura)])
+			if !strings.Contains(saida, inicio) {
+				t.Errorf("aviso %q ausente em %dx%d:\n%s", inicio, c.largura, c.altura, saida)
+			}
+			if strings.ContainsRune(saida, temaAparencia(Personagem).Simbolo) {
+				t.Errorf("personagem desenhado em tela pequena %dx%d", c.largura, c.altura)
+			}
+			if mem.Quadros != 1 {
+				t.Errorf("quadros = %d, esperado 1", mem.Quadros)
+			}
+		})
+	}
+}
+
+func TestTelaLayout(t *testing.T) {
+	casos := []struct {
+		nome              string
+		largura, altura   int
+		mapaLarg, mapaAlt int
+		posX, posY        int
+	}{
+		{"mínima", LarguraMinimaTela, AlturaMinimaTela, 60, 30, 30, 15},
+		{"canto inferior direito", 40, 20, 60, 30, 58, 28},
+		{"canto superior esquerdo", 40, 20, 60, 30, 1, 1},
+		{"mapa menor que a tela", 100, 50, 60, 30, 58, 28},
+		{"larga e baixa", 120, 12, 60, 30, 30, 28},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			jogo := telaJogoTeste(c.mapaLarg, c.mapaAlt, c.posX, c.posY)
+			mem := telaDesenharTeste(t, jogo, c.largura, c.altura)
+
+			// O aviso de tela pequena não aparece a partir do tamanho mínimo
+			if strings.Contains(mem.String(), traduzir("tela.pequena")) {
+				t.Fatalf("aviso de tela pequena em %dx%d", c.largura, c.altura)
+			}
+
+			// O HUD ocupa as últimas AlturaHUD linhas, começando pelo separador
+			topo := c.altura - AlturaHUD
+			if !strings.HasPrefix(mem.Linha(topo), "─") {
+				t.Errorf("linha %d = %q, esperado o separador do HUD", topo, mem.Linha(topo))
+			}
+
+			// A câmera usa só a área acima do HUD e fica dentro do mapa
+			cam := jogo.Camera
+			if cam.Largura != c.largura || cam.Altura != topo {
+				t.Errorf("câmera %dx%d, esperado %dx%d", cam.Largura, cam.Altura, c.largura, topo)
+			}
+			if cam.X < 0 || cam.Y < 0 {
+				t.Errorf("câmera fora do mapa em (%d, %d)", cam.X, cam.Y)
+			}
+			if cam.X > 0 && cam.X+cam.Largura > c.mapaLarg {
+				t.Errorf("câmera passa da borda direita: x=%d largura=%d mapa=%d", cam.X, cam.Largura, c.mapaLarg)
+			}
+			if cam.Y > 0 && cam.Y+cam.Altura > c.mapaAlt {
+				t.Errorf("câmera passa da borda inferior: y=%d altura=%d mapa=%d", cam.Y, cam.Altura, c.mapaAlt)
+			}
+
+			// O personagem é desenhado acima do HUD, na posição dada pela câmera
+			x, y, ok := cameraParaTela(&cam, c.posX, c.posY)
+			if !ok {
+				t.Fatalf("personagem (%d, %d) fora da câmera %+v", c.posX, c.posY, cam)
+			}
+			if y >= topo {
+				t.Errorf("personagem desenhado na linha %d, dentro do HUD (topo %d)", y, topo)
+			}
+			if ch := mem.Celulas[y][x].Ch; ch != temaAparencia(Personagem).Simbolo {
+				t.Errorf("célula (%d, %d) = %q, esperado o personagem", x, y, ch)
+			}
+		})
+	}
+}
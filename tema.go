@@ -0,0 +1,188 @@
+// tema.go - Temas visuais: símbolos e cores usados para desenhar cada elemento
+package main
+
+import (
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Aparencia é como um elemento é desenhado na tela
+type Aparencia struct {
+	Simbolo  rune
+	Cor      Cor
+	CorFundo Cor
+}
+
+// Tema troca os símbolos e as cores na hora de desenhar. Os arquivos de mapa
+// e a lógica do jogo continuam usando os símbolos canônicos dos elementos.
+type Tema struct {
+	Nome string
+
+	// Aparência própria de alguns elementos, pelo símbolo canônico
+	Elementos map[rune]Aparencia
+
+	// Substituição de símbolos, tanto no mapa quanto nos textos da interface
+	Glifos map[rune]rune
+
+	// Substituição das cores de frente e de fundo; os atributos (negrito etc.) são mantidos
+	Cores  map[Cor]Cor
+	Fundos map[Cor]Cor
+}
+
+// Nomes dos temas incluídos no jogo
+const (
+	TemaUnicode       = "unicode"
+	TemaASCII         = "ascii"
+	TemaAltoContraste = "alto-contraste"
+	TemaDaltonico     = "daltonico"
+	TemaMonocromatico = "monocromatico"
+)
+
+// Bits de um Cor que guardam a cor; os demais são atributos
+const mascaraCor Cor = termbox.AttrBold - 1
+
+// Símbolos ASCII equivalentes aos usados no mapa e na interface
+var glifosASCII = map[rune]rune{
+	'☺': '@',
+	'☠': 'M',
+	'▤': '#',
+	'♣': '%',
+	'¤': '$',
+	'★': '*',
+	'✦': '+',
+	'◉': 'O',
+	'⚷': 'k',
+	'▣': 'D',
+	'▭': '\'',
+	'⌐': '\\',
+	'¬': '/',
+	'▦': '=',
+	'▫': '_',
+	'⚑': '>',
+	'■': 'B',
+	'◇': ':',
+	'◆': '&',
+	'▲': '^',
+	'○': 'u',
+	'≈': '~',
+	'⌂': 'j',
+	'♨': '!',
+	'◎': 'P',
+	'·': '.',
+	'∙': '.',
+	'♥': 'o',
+	'⇈': '^',
+	'█': '#',
+	'░': '-',
+	'─': '-',
+	'↑': '^',
+	'↓': 'v',
+	'←': '<',
+	'→': '>',
+}
+
+var temas = []*Tema{
+	{Nome: TemaUnicode},
+	{Nome: TemaASCII, Glifos: glifosASCII},
+	{
+		Nome: TemaAltoContraste,
+		Elementos: map[rune]Aparencia{
+			Parede.simbolo: {Parede.simbolo, termbox.ColorBlack, termbox.ColorWhite},
+		},
+		Cores: map[Cor]Cor{
+			termbox.ColorBlue:     termbox.ColorLightBlue | termbox.AttrBold,
+			termbox.ColorDarkGray: termbox.ColorWhite | termbox.AttrBold,
+			termbox.ColorRed:      termbox.ColorLightRed | termbox.AttrBold,
+			termbox.ColorGreen:    termbox.ColorLightGreen | termbox.AttrBold,
+			termbox.ColorYellow:   termbox.ColorLightYellow | termbox.AttrBold,
+		},
+		Fundos: map[Cor]Cor{
+			termbox.ColorDarkGray: termbox.ColorWhite,
+		},
+	},
+	{
+		// Troca vermelho e verde, confundidos nos tipos mais comuns de
+		// daltonismo, por magenta e ciano
+		Nome: TemaDaltonico,
+		Cores: map[Cor]Cor{
+			termbox.ColorRed:   termbox.ColorMagenta,
+			termbox.ColorGreen: termbox.ColorCyan,
+		},
+	},
+	{
+		// Sem cores; a parede é distinguida pelo vídeo reverso e o que seria
+		// azul, como o monstro assustado, pelo sublinhado
+		Nome: TemaMonocromatico,
+		Elementos: map[rune]Aparencia{
+			Parede.simbolo: {Parede.simbolo, CorPadrao | termbox.AttrReverse, CorPadrao},
+		},
+		Cores: map[Cor]Cor{
+			termbox.ColorBlack:    CorPadrao,
+			termbox.ColorRed:      CorPadrao,
+			termbox.ColorGreen:    CorPadrao,
+			termbox.ColorYellow:   CorPadrao,
+			termbox.ColorBlue:     CorPadrao | termbox.AttrUnderline,
+			termbox.ColorDarkGray: CorPadrao,
+		},
+		Fundos: map[Cor]Cor{
+			termbox.ColorDarkGray: CorPadrao,
+			termbox.ColorYellow:   CorPadrao | termbox.AttrReverse,
+		},
+	},
+}
+
+// Tema usado pela interface; definido na inicialização
+var temaAtual = temas[0]
+
+// Nomes dos temas, na ordem da tela de opções
+func temasDisponiveis() []string {
+	var nomes []string
+	for _, t := range temas {
+		nomes = append(nomes, t.Nome)
+	}
+	return nomes
+}
+
+// Retorna o tema com o nome dado; vazio escolhe o tema Unicode
+func temaEscolher(nome string) (*Tema, error) {
+	if nome == "" {
+		return temas[0], nil
+	}
+	for _, t := range temas {
+		if t.Nome == strings.ToLower(nome) {
+			return t, nil
+		}
+	}
+	return temas[0], textosErro("erro.tema", nome, strings.Join(temasDisponiveis(), ", "))
+}
+
+// Aparência de um elemento do mapa no tema atual
+func temaAparencia(elem Elemento) Aparencia {
+	if a, ok := temaAtual.Elementos[elem.simbolo]; ok {
+		return a
+	}
+	return Aparencia{temaSimbolo(elem.simbolo), temaCor(elem.cor), temaFundo(elem.corFundo)}
+}
+
+func temaSimbolo(simbolo rune) rune {
+	if novo, ok := temaAtual.Glifos[simbolo]; ok {
+		return novo
+	}
+	return simbolo
+}
+
+func temaCor(cor Cor) Cor {
+	return temaTrocarCor(temaAtual.Cores, cor)
+}
+
+func temaFundo(cor Cor) Cor {
+	return temaTrocarCor(temaAtual.Fundos, cor)
+}
+
+func temaTrocarCor(trocas map[Cor]Cor, cor Cor) Cor {
+	if nova, ok := trocas[cor&mascaraCor]; ok {
+		return nova | cor&^mascaraCor
+	}
+	return cor
+}
@@ -0,0 +1,128 @@
+// textos.go - Catálogos de textos exibidos ao jogador, por idioma
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Idiomas com catálogo incluído no jogo
+const (
+	IdiomaPortugues = "pt-BR"
+	IdiomaIngles    = "en"
+)
+
+// Catalogo associa o identificador de cada texto à sua tradução. Os textos
+// usam os verbos do fmt para os parâmetros; %[n]s permite reordená-los.
+type Catalogo map[string]string
+
+var catalogos = map[string]Catalogo{
+	IdiomaPortugues: catalogoPortugues,
+	IdiomaIngles:    catalogoIngles,
+}
+
+// Idioma usado por traduzir; definido na inicialização
+var idiomaAtual = IdiomaPortugues
+
+// Retorna o texto do identificador no idioma atual, com os parâmetros aplicados.
+// Sem tradução, usa o texto em português e, por último, o próprio identificador.
+func traduzir(id string, args ...interface{}) string {
+	formato, ok := catalogos[idiomaAtual][id]
+	if !ok {
+		formato, ok = catalogos[IdiomaPortugues][id]
+	}
+	if !ok {
+		formato = id
+	}
+	if len(args) == 0 {
+		return formato
+	}
+	return fmt.Sprintf(formato, args...)
+}
+
+// ErroTexto é um erro com um texto do catálogo. Ele é traduzido ao ser
+// exibido, no idioma escolhido até lá: erros da leitura das configurações
+// surgem antes de o idioma ser conhecido.
+type ErroTexto struct {
+	ID   string
+	Args []interface{}
+}
+
+func (e ErroTexto) Error() string {
+	return traduzir(e.ID, e.Args...)
+}
+
+// Retorna um erro com o texto do identificador; os parâmetros podem ser
+// outros erros, traduzidos junto
+func textosErro(id string, args ...interface{}) error {
+	return ErroTexto{ID: id, Args: args}
+}
+
+// Escolhe o idioma: o pedido explicitamente (flag ou configuração) ou,
+// se vazio, o das variáveis de ambiente do sistema
+func idiomaEscolher(pedido string) string {
+	if pedido == "" {
+		for _, variavel := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			if valor := os.Getenv(variavel); valor != "" {
+				pedido = valor
+				break
+			}
+		}
+	}
+	return idiomaNormalizar(pedido)
+}
+
+// Converte nomes como "en_US.UTF-8" ou "pt_BR" para um idioma com catálogo
+func idiomaNormalizar(nome string) string {
+	nome = strings.ToLower(nome)
+	switch {
+	case strings.HasPrefix(nome, "en"):
+		return IdiomaIngles
+	default:
+		return IdiomaPortugues
+	}
+}
+
+// Idiomas disponíveis em ordem estável, para a tela de opções
+func idiomasDisponiveis() []string {
+	var idiomas []string
+	for idioma := range catalogos {
+		idiomas = append(idiomas, idioma)
+	}
+	sort.Strings(idiomas)
+	return idiomas
+}
+
+// Quantidade de parâmetros usados por um texto ("%%" não conta)
+func textosParametros(formato string) int {
+	return strings.Count(formato, "%") - 2*strings.Count(formato, "%%")
+}
+
+// Verifica se todos os identificadores existem em todos os catálogos e se
+// as traduções usam a mesma quantidade de parâmetros que o texto em português
+func textosVerificar() error {
+	ids := make(map[string]bool)
+	for _, cat := range catalogos {
+		for id := range cat {
+			ids[id] = true
+		}
+	}
+	var faltando []string
+	for _, idioma := range idiomasDisponiveis() {
+		for id := range ids {
+			texto, ok := catalogos[idioma][id]
+			if !ok {
+				faltando = append(faltando, idioma+": "+id)
+			} else if original, ok := catalogos[IdiomaPortugues][id]; ok && textosParametros(texto) != textosParametros(original) {
+				faltando = append(faltando, idioma+": "+id+" (parâmetros)")
+			}
+		}
+	}
+	if len(faltando) == 0 {
+		return nil
+	}
+	sort.Strings(faltando)
+	return fmt.Errorf("textos sem tradução ou com parâmetros diferentes:\n%s", strings.Join(faltando, "\n"))
+}
@@ -0,0 +1,282 @@
+// textos_en.go - Catálogo de textos em inglês
+package main
+
+var catalogoIngles = Catalogo{
+	// Mensagens do jogo
+	"interagir.nada":             "Nothing to interact with",
+	"porta.trancada":             "The door is locked (key %s)",
+	"porta.aberta":               "Door %s opened!",
+	"chave.coletada":             "Key %s collected!",
+	"alavanca.acionada":          "Lever %s pulled",
+	"alavanca.bloqueada":         "Lever %s pulled (%d gates blocked)",
+	"bloco.preso":                "The block does not move",
+	"placa.abriu":                "Plates %s pressed: gates opened",
+	"placa.fechou":               "Plates %s released: gates closed",
+	"placa.bloqueada":            "Plates %s released (%d gates blocked)",
+	"quebra_cabeca.reiniciado":   "Blocks back at their starting positions",
+	"quebra_cabeca.bloqueado":    "Cannot reset: someone is standing where a block starts",
+	"quebra_cabeca.sem_blocos":   "There are no blocks in this level",
+	"perigo.espinhos":            "Spikes! Lives left: %d",
+	"perigo.chamas":              "Burned by the flame jet! Lives left: %d",
+	"perigo.fim":                 "The traps got you!",
+	"perigo.buraco":              "You fell into a pit and went back to the start",
+	"perigo.lama":                "Stuck in the mud: your next move only frees you",
+	"perigo.lama_solto":          "You broke free of the mud",
+	"perigo.monstro_buraco":      "The monster fell into a pit and went back to its origin",
+	"perigo.monstro_chamas":      "The monster was burned and went back to its origin",
+	"portal.atravessou":          "You went through the portal to (%d, %d)",
+	"portal.monstro":             "The monster went through the portal to (%d, %d)",
+	"inventario.cheio":           "Inventory full!",
+	"inventario.guardado":        "Stored in the inventory: %s",
+	"inventario.vazio":           "Inventory slot is empty",
+	"inventario.titulo":          "Inventory:",
+	"item.invisibilidade":        "invisibility",
+	"item.pulo_duplo":            "double jump",
+	"monstro.perdeu_rastro":      "The monster lost track of you and is patrolling aggressively",
+	"monstro.pego":               "Caught by the monster!",
+	"monstro.pego_vidas":         "Caught by the monster! Lives left: %d",
+	"monstro.capturado":          "Monster caught! +%d",
+	"monstro.reapareceu":         "The monster is back at its origin",
+	"poder.ativado":              "Power! Monsters flee from you for %d moves",
+	"poder.expirou":              "The power is gone",
+	"invisibilidade.ativada":     "Invisibility activated!",
+	"invisibilidade.expirou":     "Invisibility expired",
+	"invisibilidade.restante":    "Invisible: %d moves left",
+	"estrela.coletada":           "Star collected! %s +%d",
+	"estrela.bonus.score":        "score",
+	"estrela.bonus.power":        "power",
+	"estrela.bonus.life":         "life",
+	"estrela.estado":             "Star %s changed state",
+	"estrela.pulsando":           "Star pulsing (%d pulses)",
+	"estrela.carregada":          "Star charged! Energy: %d",
+	"estrela.timeout":            "Star %s changed behaviour (%s)",
+	"estrela.comunicando":        "Star %s → %s: %s",
+	"estrela.acao.charge":        "charge",
+	"estrela.acao.pulse":         "pulse",
+	"estrela.acao.hide":          "hide",
+	"estrela.acao.energy_burst":  "energy burst",
+	"estrela.sinal.sync_pulse":   "sync pulse",
+	"estrela.sinal.share_energy": "share energy",
+	"estrela.sinal.warning":      "warning",
+	"pulo.ativado":               "Double jump activated! %d jumps",
+	"pulo.usado":                 "Double jump! %d jumps left",
+	"pulo.bloqueado":             "Double jump blocked! %d jumps left",
+	"pulo.bloqueado_normal":      "Double jump blocked - normal move. %d jumps left",
+	"pulo.ultimo":                "Last double jump used!",
+	"nivel.concluido":            "Level complete!",
+	"save.desativado":            "Saving is disabled in this mode",
+	"save.erro":                  "Error saving: %v",
+	"save.salvo":                 "Game saved to %s",
+	"severidade.detalhe":         "detail",
+	"severidade.info":            "info",
+	"severidade.aviso":           "warning",
+	"severidade.alerta":          "alert",
+
+	// HUD
+	"hud.pontos":    "Score: %d",
+	"hud.vidas":     "Lives: %s",
+	"hud.tempo":     "Time: %s",
+	"hud.estrelas":  "Stars: %d/%d",
+	"hud.pulos":     "Double jumps: %d",
+	"hud.invisivel": "Invisible %s",
+	"hud.poder":     "Power %s",
+	"hud.cacado":    "! HUNTED !",
+	"hud.oculto":    "Hidden",
+	"hud.ajuda":     "%s: move  %s: interact  1-9: use item  %s: save  %s: messages  %s: pause",
+	"tela.pequena":  "Terminal too small",
+	"tela.minimo":   "Minimum: %dx%d",
+
+	// Menus
+	"menu.titulo":           "FPPD GAME",
+	"menu.novo":             "New game",
+	"menu.continuar":        "Continue",
+	"menu.selecionar_nivel": "Select level",
+	"menu.niveis":           "SELECT LEVEL",
+	"menu.recordes":         "High scores",
+	"menu.opcoes":           "Options",
+	"menu.sair":             "Quit",
+	"menu.salvar":           "Save",
+	"menu.ajuda":            "↑/↓: navigate  ←/→: change  Enter: select  ESC: back",
+	"menu.sim":              "Yes",
+	"menu.nao":              "No",
+	"pausa.titulo":          "PAUSED",
+	"pausa.confirmar_saida": "Leave the game? Progress will be saved.",
+	"opcoes.titulo":         "OPTIONS",
+	"opcoes.zona_x":         "Horizontal dead zone",
+	"opcoes.zona_y":         "Vertical dead zone",
+	"opcoes.detalhes":       "Detailed messages",
+	"opcoes.neblina":        "Fog of war",
+	"opcoes.teclas":         "Keys",
+	"opcoes.idioma":         "Language",
+	"opcoes.idioma_sistema": "system",
+	"opcoes.captura":        "Snapshot format",
+	"opcoes.captura_rota":   "Snapshot: monster path",
+	"opcoes.captura_itens":  "Snapshot: reachable items",
+	"opcoes.tema":           "Theme",
+	"tema.unicode":          "Unicode",
+	"tema.ascii":            "ASCII",
+	"tema.alto-contraste":   "high contrast",
+	"tema.daltonico":        "colour-blind safe",
+	"tema.monocromatico":    "monochrome",
+	"opcoes.sim":            "yes",
+	"opcoes.nao":            "no",
+	"opcoes.erro_salvar":    "Could not save the options: %v",
+
+	// Recordes
+	"recordes.titulo":      "HIGH SCORES",
+	"recordes.vazio":       "No high scores yet",
+	"recordes.nome":        "Name",
+	"recordes.pontos":      "Score",
+	"recordes.nivel":       "Level",
+	"recordes.tempo":       "Time",
+	"recordes.semente":     "Seed",
+	"recordes.ajuda":       "←/→: other map  ESC/Enter: back",
+	"recordes.fim_de_jogo": "Game over!",
+	"recordes.concluida":   "Campaign complete!",
+	"recordes.digite_nome": "Score: %d  Enter your name:",
+	"recordes.anonimo":     "Anonymous",
+
+	// Registro de mensagens
+	"mensagens.titulo":       "MESSAGES (%d, %s)",
+	"mensagens.com_detalhes": "with details",
+	"mensagens.sem_detalhes": "without details",
+	"mensagens.ajuda":        "↑/↓ PgUp/PgDn: scroll  F: details  ESC/L: back",
+
+	// Tela de teclas
+	"teclas.titulo":            "KEYS",
+	"teclas.ajuda":             "Enter: replace  A: add  Del: clear  R: defaults  ESC: back",
+	"teclas.pausa_obrigatoria": "Pause needs at least one key",
+	"teclas.restauradas":       "Default keys restored",
+	"teclas.pressione":         "Press the key for \"%s\" (ESC cancels)",
+	"teclas.retirada":          "%s removed from \"%s\"",
+	"teclas.conflitos":         "conflicting keys: %s",
+	"teclas.conflito":          "%s in %s",
+	"tecla.espaco":             "Space",
+	"tecla.numerica":           "Num %s",
+	"acao.cima":                "Move up",
+	"acao.baixo":               "Move down",
+	"acao.esquerda":            "Move left",
+	"acao.direita":             "Move right",
+	"acao.interagir":           "Interact",
+	"acao.salvar":              "Save",
+	"acao.mensagens":           "Messages",
+	"acao.pausar":              "Pause",
+	"acao.reiniciar":           "Reset puzzle",
+	"acao.depurar":             "Debug overlay",
+	"acao.console":             "Console",
+	"acao.capturar":            "Snapshot",
+	"acao.item":                "Use item %s",
+
+	// Editor de mapas
+	"editor.novo":                 "New map",
+	"editor.nada_desfazer":        "Nothing to undo",
+	"editor.desfeito":             "Undone",
+	"editor.nada_refazer":         "Nothing to redo",
+	"editor.refeito":              "Redone",
+	"editor.retangulo_inicio":     "Move the cursor to the opposite corner and press space",
+	"editor.retangulo":            "Filled %dx%d rectangle",
+	"editor.sem_personagem":       "No player start position",
+	"editor.personagens":          "%d players; only the last one will be used",
+	"editor.inimigos":             "%d enemies; only the first one will be used",
+	"editor.desconhecidos":        "%d unknown symbols will be read as empty",
+	"editor.borda":                "Map border is not fully closed by walls",
+	"editor.nao_salvo":            "Unsaved changes! Ctrl+S to save or Ctrl+Q to quit without saving",
+	"editor.erro_salvar":          "Error saving: %v",
+	"editor.salvo":                "Map saved to %s",
+	"editor.ferramenta":           "Tool: %s",
+	"editor.ferramenta.pincel":    "brush",
+	"editor.ferramenta.retangulo": "rectangle",
+	"editor.ajuda1":               "Arrows: cursor  1-9/Tab: palette  [ ]: page  Space: place  X: erase  F: fill",
+	"editor.ajuda2":               "R: rectangle  U: undo  Y: redo  Ctrl+S: save  ESC: quit  Ctrl+Q: don't save",
+
+	// Sobreposição de depuração
+	"depuracao.goroutines":         "goroutines: %d",
+	"depuracao.fila":               "event queue: %d/%d",
+	"depuracao.descartados":        "dropped events: %d",
+	"depuracao.estrela":            "%s e=%d p=%d",
+	"depuracao.estrela.visivel":    "visible",
+	"depuracao.estrela.invisivel":  "invisible",
+	"depuracao.estrela.pulsando":   "pulsing",
+	"depuracao.estrela.carregando": "charging",
+
+	// Capturas e exportação
+	"captura.salva":                 "Snapshot saved to %s",
+	"captura.erro":                  "Could not save the snapshot: %v",
+	"exportar.legenda.caminho":      "Monster planned path",
+	"exportar.legenda.alcancavel":   "Item reachable by the player",
+	"exportar.legenda.inalcancavel": "Item out of the player's reach",
+	"exportar.uso":                  "usage: jogo export <map> [-formato svg|html] [-saida file] [-caminhos] [-alcance]",
+	"exportar.formato_desconhecido": "unknown export format: %s (use %s or %s)",
+	"exportar.opcao.formato":        "file format: svg or html",
+	"exportar.opcao.saida":          "output file (default: the map name with the format extension)",
+	"exportar.opcao.caminhos":       "marks the monster's planned path",
+	"exportar.opcao.alcance":        "marks the items the player can or cannot reach",
+
+	// Console de desenvolvimento
+	"console.ajuda":                "Enter runs, Tab completes, ↑/↓ history, ESC closes",
+	"console.gravando":             "the console is disabled while recording",
+	"console.desconhecido":         "unknown command: %s (try help)",
+	"console.uso":                  "usage: %s",
+	"console.uso.give":             "give invis|jump|life|power|score <n>",
+	"console.uso.help":             "help",
+	"console.uso.monster":          "monster <id> state hunting|patrolling",
+	"console.uso.spawn":            "spawn <symbol> <x> <y>",
+	"console.uso.star":             "star <id> pulse|charge|state <state>",
+	"console.uso.teleport":         "teleport <x> <y>",
+	"console.estrela_desconhecida": "unknown star: %s",
+	"console.monstro_desconhecido": "unknown monster: %s",
+	"console.estado_invalido":      "invalid state: %s",
+	"console.nao_entregue":         "command not delivered: channel full",
+	"console.posicao_invalida":     "invalid position: %d,%d",
+	"console.simbolo_desconhecido": "unknown symbol: %s",
+	"console.estrela_ok":           "%s sent to %s",
+	"console.monstro_ok":           "%s now %s",
+	"console.teleporte_ok":         "player at %d,%d",
+	"console.dar_ok":               "+%d %s",
+	"console.estrela_criada":       "star %s created at %d,%d",
+	"console.criado":               "%s created at %d,%d",
+
+	// Geradores de itens
+	"gerador.item":           "%s appeared at %d,%d",
+	"gerador.estrela":        "Star",
+	"gerador.invisibilidade": "Invisibility item",
+
+	// Linha de comando
+	"aviso":               "warning: %v",
+	"aviso.configuracoes": "warning: settings: %v",
+	"uso.editor":          "usage: jogo edit <map>",
+	"uso.replay":          "usage: jogo [-velocidade N] replay <file>",
+	"textos.completos":    "complete catalogs: %s",
+
+	// Erros ao ler mapas, saves, replays, temas e tiles
+	"erro.portal_par":             "portal %s needs exactly two tiles (found %d)",
+	"erro.tema":                   "unknown theme %q (available: %s)",
+	"erro.metadado":               "invalid metadata %q: %v",
+	"erro.metadado_portal":        "expected: @portal id personagem",
+	"erro.gerador_formato":        "expected: @gerador item cooldown maximum [x,y ...]",
+	"erro.gerador_item":           "unknown item %q (use %s or %s)",
+	"erro.gerador_recarga":        "invalid cooldown %q",
+	"erro.gerador_maximo":         "invalid maximum %q",
+	"erro.gerador_posicao":        "invalid position %q",
+	"erro.save_versao":            "save version %d not supported (expected %d to %d)",
+	"erro.save_invalido":          "invalid save: %v",
+	"erro.save_automatico":        "autosave: %v",
+	"erro.replay_invalido":        "invalid replay: %v",
+	"erro.replay_versao":          "replay version %d not supported (expected %d)",
+	"erro.replay_nivel":           "replay without the map of level %d",
+	"erro.replay_tipo":            "unknown data type %s",
+	"erro.replay_gravar":          "event %s: %v",
+	"erro.replay_evento":          "event %s at tick %d: %v",
+	"erro.tiles_arquivo":          "%s: %v",
+	"erro.tiles_tile":             "%s: tile %q: %v",
+	"erro.tile_sem_nome":          "missing name",
+	"erro.tile_simbolo":           "symbol %q must be a single character",
+	"erro.tile_simbolo_fixo":      "the symbol of an existing type cannot be changed",
+	"erro.tile_sem_simbolo":       "new type without a symbol",
+	"erro.tile_simbolo_usado":     "symbol %q already used by %s",
+	"erro.tile_como_existente":    "\"como\" only applies to new types",
+	"erro.tile_como_desconhecido": "unknown type %q in \"como\"",
+	"erro.tile_como_proibido":     "type %q cannot be used in \"como\"",
+	"erro.tile_cor":               "unknown color %q",
+	"erro.tile_abafamento":        "muffling %v outside 0 to 1",
+}
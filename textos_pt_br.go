@@ -0,0 +1,282 @@
+// textos_pt_br.go - Catálogo de textos em português
+package main
+
+var catalogoPortugues = Catalogo{
+	// Mensagens do jogo
+	"interagir.nada":             "Nada para interagir",
+	"porta.trancada":             "A porta está trancada (chave %s)",
+	"porta.aberta":               "Porta %s aberta!",
+	"chave.coletada":             "Chave %s coletada!",
+	"alavanca.acionada":          "Alavanca %s acionada",
+	"alavanca.bloqueada":         "Alavanca %s acionada (%d portões bloqueados)",
+	"bloco.preso":                "O bloco não se move",
+	"placa.abriu":                "Placas %s pressionadas: portões abertos",
+	"placa.fechou":               "Placas %s liberadas: portões fechados",
+	"placa.bloqueada":            "Placas %s liberadas (%d portões bloqueados)",
+	"quebra_cabeca.reiniciado":   "Blocos de volta às posições iniciais",
+	"quebra_cabeca.bloqueado":    "Não é possível reiniciar: há alguém na posição de um bloco",
+	"quebra_cabeca.sem_blocos":   "Não há blocos neste nível",
+	"perigo.espinhos":            "Espinhos! Vidas restantes: %d",
+	"perigo.chamas":              "Queimado pelo jato de chamas! Vidas restantes: %d",
+	"perigo.fim":                 "Você não resistiu às armadilhas!",
+	"perigo.buraco":              "Você caiu em um buraco e voltou ao início",
+	"perigo.lama":                "Preso na lama: o próximo movimento só o solta",
+	"perigo.lama_solto":          "Você se soltou da lama",
+	"perigo.monstro_buraco":      "O monstro caiu em um buraco e voltou à origem",
+	"perigo.monstro_chamas":      "O monstro foi queimado e voltou à origem",
+	"portal.atravessou":          "Você atravessou o portal para (%d, %d)",
+	"portal.monstro":             "O monstro atravessou o portal para (%d, %d)",
+	"inventario.cheio":           "Inventário cheio!",
+	"inventario.guardado":        "Guardado no inventário: %s",
+	"inventario.vazio":           "Espaço do inventário vazio",
+	"inventario.titulo":          "Inventário:",
+	"item.invisibilidade":        "invisibilidade",
+	"item.pulo_duplo":            "pulo duplo",
+	"monstro.perdeu_rastro":      "O monstro perdeu seu rastro e patrulha agressivamente",
+	"monstro.pego":               "Pego pelo monstro!",
+	"monstro.pego_vidas":         "Pego pelo monstro! Vidas restantes: %d",
+	"monstro.capturado":          "Monstro capturado! +%d",
+	"monstro.reapareceu":         "O monstro voltou ao ponto de origem",
+	"poder.ativado":              "Poder! Os monstros fogem de você por %d movimentos",
+	"poder.expirou":              "O poder acabou",
+	"invisibilidade.ativada":     "Invisibilidade ativada!",
+	"invisibilidade.expirou":     "Invisibilidade expirou",
+	"invisibilidade.restante":    "Invisível: %d movimentos restantes",
+	"estrela.coletada":           "Estrela coletada! %s +%d",
+	"estrela.bonus.score":        "pontos",
+	"estrela.bonus.power":        "poder",
+	"estrela.bonus.life":         "vida",
+	"estrela.estado":             "Estrela %s mudou de estado",
+	"estrela.pulsando":           "Estrela pulsando (%d pulsos)",
+	"estrela.carregada":          "Estrela carregada! Energia: %d",
+	"estrela.timeout":            "Estrela %s mudou de comportamento (%s)",
+	"estrela.comunicando":        "Estrela %s → %s: %s",
+	"estrela.acao.charge":        "carregar",
+	"estrela.acao.pulse":         "pulsar",
+	"estrela.acao.hide":          "esconder",
+	"estrela.acao.energy_burst":  "explosão de energia",
+	"estrela.sinal.sync_pulse":   "sincronizar pulso",
+	"estrela.sinal.share_energy": "dividir energia",
+	"estrela.sinal.warning":      "alerta",
+	"pulo.ativado":               "Pulo duplo ativado! %d pulos",
+	"pulo.usado":                 "Pulo duplo! Restam %d pulos",
+	"pulo.bloqueado":             "Pulo duplo bloqueado! Restam %d pulos",
+	"pulo.bloqueado_normal":      "Pulo duplo bloqueado - movimento normal. Restam %d pulos",
+	"pulo.ultimo":                "Último pulo duplo usado!",
+	"nivel.concluido":            "Nível concluído!",
+	"save.desativado":            "Salvar desativado neste modo",
+	"save.erro":                  "Erro ao salvar: %v",
+	"save.salvo":                 "Jogo salvo em %s",
+	"severidade.detalhe":         "detalhe",
+	"severidade.info":            "info",
+	"severidade.aviso":           "aviso",
+	"severidade.alerta":          "alerta",
+
+	// HUD
+	"hud.pontos":    "Pontos: %d",
+	"hud.vidas":     "Vidas: %s",
+	"hud.tempo":     "Tempo: %s",
+	"hud.estrelas":  "Estrelas: %d/%d",
+	"hud.pulos":     "Pulos duplos: %d",
+	"hud.invisivel": "Invisível %s",
+	"hud.poder":     "Poder %s",
+	"hud.cacado":    "! CAÇADO !",
+	"hud.oculto":    "Oculto",
+	"hud.ajuda":     "%s: mover  %s: interagir  1-9: usar item  %s: salvar  %s: mensagens  %s: pausa",
+	"tela.pequena":  "Terminal muito pequeno",
+	"tela.minimo":   "Mínimo: %dx%d",
+
+	// Menus
+	"menu.titulo":           "FPPD JOGO",
+	"menu.novo":             "Novo jogo",
+	"menu.continuar":        "Continuar",
+	"menu.selecionar_nivel": "Selecionar nível",
+	"menu.niveis":           "SELECIONAR NÍVEL",
+	"menu.recordes":         "Recordes",
+	"menu.opcoes":           "Opções",
+	"menu.sair":             "Sair",
+	"menu.salvar":           "Salvar",
+	"menu.ajuda":            "↑/↓: navegar  ←/→: alterar  Enter: escolher  ESC: voltar",
+	"menu.sim":              "Sim",
+	"menu.nao":              "Não",
+	"pausa.titulo":          "PAUSA",
+	"pausa.confirmar_saida": "Sair da partida? O jogo será salvo.",
+	"opcoes.titulo":         "OPÇÕES",
+	"opcoes.zona_x":         "Zona morta horizontal",
+	"opcoes.zona_y":         "Zona morta vertical",
+	"opcoes.detalhes":       "Mensagens detalhadas",
+	"opcoes.neblina":        "Neblina de guerra",
+	"opcoes.teclas":         "Teclas",
+	"opcoes.idioma":         "Idioma",
+	"opcoes.idioma_sistema": "do sistema",
+	"opcoes.captura":        "Formato da captura",
+	"opcoes.captura_rota":   "Captura: caminho do monstro",
+	"opcoes.captura_itens":  "Captura: itens alcançáveis",
+	"opcoes.tema":           "Tema",
+	"tema.unicode":          "Unicode",
+	"tema.ascii":            "ASCII",
+	"tema.alto-contraste":   "alto contraste",
+	"tema.daltonico":        "para daltônicos",
+	"tema.monocromatico":    "monocromático",
+	"opcoes.sim":            "sim",
+	"opcoes.nao":            "não",
+	"opcoes.erro_salvar":    "Não foi possível salvar as opções: %v",
+
+	// Recordes
+	"recordes.titulo":      "RECORDES",
+	"recordes.vazio":       "Nenhum recorde ainda",
+	"recordes.nome":        "Nome",
+	"recordes.pontos":      "Pontos",
+	"recordes.nivel":       "Nível",
+	"recordes.tempo":       "Tempo",
+	"recordes.semente":     "Semente",
+	"recordes.ajuda":       "←/→: outro mapa  ESC/Enter: voltar",
+	"recordes.fim_de_jogo": "Fim de jogo!",
+	"recordes.concluida":   "Campanha concluída!",
+	"recordes.digite_nome": "Pontos: %d  Digite seu nome:",
+	"recordes.anonimo":     "Anônimo",
+
+	// Registro de mensagens
+	"mensagens.titulo":       "MENSAGENS (%d, %s)",
+	"mensagens.com_detalhes": "com detalhes",
+	"mensagens.sem_detalhes": "sem detalhes",
+	"mensagens.ajuda":        "↑/↓ PgUp/PgDn: rolar  F: detalhes  ESC/L: voltar",
+
+	// Tela de teclas
+	"teclas.titulo":            "TECLAS",
+	"teclas.ajuda":             "Enter: trocar  A: acrescentar  Del: limpar  R: padrão  ESC: voltar",
+	"teclas.pausa_obrigatoria": "A pausa precisa de ao menos uma tecla",
+	"teclas.restauradas":       "Teclas padrão restauradas",
+	"teclas.pressione":         "Pressione a tecla para \"%s\" (ESC cancela)",
+	"teclas.retirada":          "%s retirada de \"%s\"",
+	"teclas.conflitos":         "teclas em conflito: %s",
+	"teclas.conflito":          "%s em %s",
+	"tecla.espaco":             "Espaço",
+	"tecla.numerica":           "Num %s",
+	"acao.cima":                "Mover para cima",
+	"acao.baixo":               "Mover para baixo",
+	"acao.esquerda":            "Mover para esquerda",
+	"acao.direita":             "Mover para direita",
+	"acao.interagir":           "Interagir",
+	"acao.salvar":              "Salvar",
+	"acao.mensagens":           "Mensagens",
+	"acao.pausar":              "Pausar",
+	"acao.reiniciar":           "Reiniciar quebra-cabeça",
+	"acao.depurar":             "Depuração",
+	"acao.console":             "Console",
+	"acao.capturar":            "Captura de tela",
+	"acao.item":                "Usar item %s",
+
+	// Editor de mapas
+	"editor.novo":                 "Novo mapa",
+	"editor.nada_desfazer":        "Nada para desfazer",
+	"editor.desfeito":             "Desfeito",
+	"editor.nada_refazer":         "Nada para refazer",
+	"editor.refeito":              "Refeito",
+	"editor.retangulo_inicio":     "Mova o cursor até o canto oposto e pressione espaço",
+	"editor.retangulo":            "Retângulo %dx%d preenchido",
+	"editor.sem_personagem":       "Sem posição inicial do personagem",
+	"editor.personagens":          "%d personagens; apenas o último será usado",
+	"editor.inimigos":             "%d inimigos; apenas o primeiro será usado",
+	"editor.desconhecidos":        "%d símbolos desconhecidos serão lidos como vazio",
+	"editor.borda":                "Borda do mapa não está totalmente fechada por paredes",
+	"editor.nao_salvo":            "Alterações não salvas! Ctrl+S para salvar ou Ctrl+Q para sair sem salvar",
+	"editor.erro_salvar":          "Erro ao salvar: %v",
+	"editor.salvo":                "Mapa salvo em %s",
+	"editor.ferramenta":           "Ferramenta: %s",
+	"editor.ferramenta.pincel":    "pincel",
+	"editor.ferramenta.retangulo": "retângulo",
+	"editor.ajuda1":               "Setas: cursor  1-9/Tab: paleta  [ ]: página  Espaço: colocar  X: apagar",
+	"editor.ajuda2":               "F: preencher  R: retângulo  U: desfazer  Y: refazer  Ctrl+S: salvar  ESC: sair",
+
+	// Sobreposição de depuração
+	"depuracao.goroutines":         "goroutines: %d",
+	"depuracao.fila":               "fila de eventos: %d/%d",
+	"depuracao.descartados":        "eventos descartados: %d",
+	"depuracao.estrela":            "%s e=%d p=%d",
+	"depuracao.estrela.visivel":    "visível",
+	"depuracao.estrela.invisivel":  "invisível",
+	"depuracao.estrela.pulsando":   "pulsando",
+	"depuracao.estrela.carregando": "carregando",
+
+	// Capturas e exportação
+	"captura.salva":                 "Captura salva em %s",
+	"captura.erro":                  "Não foi possível salvar a captura: %v",
+	"exportar.legenda.caminho":      "Caminho planejado do monstro",
+	"exportar.legenda.alcancavel":   "Item alcançável pelo personagem",
+	"exportar.legenda.inalcancavel": "Item fora do alcance do personagem",
+	"exportar.uso":                  "uso: jogo export <mapa> [-formato svg|html] [-saida arquivo] [-caminhos] [-alcance]",
+	"exportar.formato_desconhecido": "formato de exportação desconhecido: %s (use %s ou %s)",
+	"exportar.opcao.formato":        "formato do arquivo: svg ou html",
+	"exportar.opcao.saida":          "arquivo gerado (padrão: o nome do mapa com a extensão do formato)",
+	"exportar.opcao.caminhos":       "marca o caminho planejado do monstro",
+	"exportar.opcao.alcance":        "marca os itens que o personagem alcança ou não",
+
+	// Console de desenvolvimento
+	"console.ajuda":                "Enter executa, Tab completa, ↑/↓ histórico, ESC fecha",
+	"console.gravando":             "o console fica desativado durante a gravação",
+	"console.desconhecido":         "comando desconhecido: %s (use help)",
+	"console.uso":                  "uso: %s",
+	"console.uso.give":             "give invis|jump|life|power|score <n>",
+	"console.uso.help":             "help",
+	"console.uso.monster":          "monster <id> state hunting|patrolling",
+	"console.uso.spawn":            "spawn <símbolo> <x> <y>",
+	"console.uso.star":             "star <id> pulse|charge|state <estado>",
+	"console.uso.teleport":         "teleport <x> <y>",
+	"console.estrela_desconhecida": "estrela desconhecida: %s",
+	"console.monstro_desconhecido": "monstro desconhecido: %s",
+	"console.estado_invalido":      "estado inválido: %s",
+	"console.nao_entregue":         "comando não entregue: canal cheio",
+	"console.posicao_invalida":     "posição inválida: %d,%d",
+	"console.simbolo_desconhecido": "símbolo desconhecido: %s",
+	"console.estrela_ok":           "%s enviado para %s",
+	"console.monstro_ok":           "%s agora em %s",
+	"console.teleporte_ok":         "personagem em %d,%d",
+	"console.dar_ok":               "+%d %s",
+	"console.estrela_criada":       "estrela %s criada em %d,%d",
+	"console.criado":               "%s criado em %d,%d",
+
+	// Geradores de itens
+	"gerador.item":           "%s apareceu em %d,%d",
+	"gerador.estrela":        "Estrela",
+	"gerador.invisibilidade": "Item de invisibilidade",
+
+	// Linha de comando
+	"aviso":               "aviso: %v",
+	"aviso.configuracoes": "aviso: configurações: %v",
+	"uso.editor":          "uso: jogo edit <mapa>",
+	"uso.replay":          "uso: jogo [-velocidade N] replay <arquivo>",
+	"textos.completos":    "catálogos completos: %s",
+
+	// Erros ao ler mapas, saves, replays, temas e tiles
+	"erro.portal_par":             "portal %s precisa de exatamente dois tiles (encontrados %d)",
+	"erro.tema":                   "tema %q desconhecido (disponíveis: %s)",
+	"erro.metadado":               "metadado inválido %q: %v",
+	"erro.metadado_portal":        "esperado: @portal id personagem",
+	"erro.gerador_formato":        "esperado: @gerador item recarga máximo [x,y ...]",
+	"erro.gerador_item":           "item %q desconhecido (use %s ou %s)",
+	"erro.gerador_recarga":        "recarga inválida %q",
+	"erro.gerador_maximo":         "máximo inválido %q",
+	"erro.gerador_posicao":        "posição inválida %q",
+	"erro.save_versao":            "versão do save %d não suportada (esperada de %d a %d)",
+	"erro.save_invalido":          "save inválido: %v",
+	"erro.save_automatico":        "save automático: %v",
+	"erro.replay_invalido":        "replay inválido: %v",
+	"erro.replay_versao":          "versão do replay %d não suportada (esperada %d)",
+	"erro.replay_nivel":           "replay sem o mapa do nível %d",
+	"erro.replay_tipo":            "tipo de dados desconhecido %s",
+	"erro.replay_gravar":          "evento %s: %v",
+	"erro.replay_evento":          "evento %s no tick %d: %v",
+	"erro.tiles_arquivo":          "%s: %v",
+	"erro.tiles_tile":             "%s: tile %q: %v",
+	"erro.tile_sem_nome":          "sem nome",
+	"erro.tile_simbolo":           "símbolo %q deve ter um caractere",
+	"erro.tile_simbolo_fixo":      "o símbolo de um tipo existente não pode ser alterado",
+	"erro.tile_sem_simbolo":       "tipo novo sem símbolo",
+	"erro.tile_simbolo_usado":     "símbolo %q já usado por %s",
+	"erro.tile_como_existente":    "\"como\" só vale para tipos novos",
+	"erro.tile_como_desconhecido": "tipo %q desconhecido em \"como\"",
+	"erro.tile_como_proibido":     "o tipo %q não pode ser usado em \"como\"",
+	"erro.tile_cor":               "cor %q desconhecida",
+	"erro.tile_abafamento":        "abafamento %v fora de 0 a 1",
+}
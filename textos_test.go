@@ -0,0 +1,96 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTextosCatalogosCompletos(t *testing.T) {
+	if err := textosVerificar(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Parâmetro com o identificador do texto em cada função que recebe um
+var textosFuncoesComID = map[string]int{
+	"traduzir":     0,
+	"jogoMensagem": 2,
+	"textosErro":   0,
+}
+
+// Todo identificador literal passado a traduzir ou jogoMensagem precisa
+// existir no catálogo; um texto escrito direto no código não seria traduzido
+func TestTextosIdentificadoresNoCatalogo(t *testing.T) {
+	arquivos, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, nome := range arquivos {
+		if strings.HasSuffix(nome, "_test.go") {
+			continue
+		}
+		arq, err := parser.ParseFile(fset, nome, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(arq, func(n ast.Node) bool {
+			chamada, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			funcao, ok := chamada.Fun.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			indice, ok := textosFuncoesComID[funcao.Name]
+			if !ok || indice >= len(chamada.Args) {
+				return true
+			}
+			// Identificadores montados em tempo de execução não são conferidos
+			lit, ok := chamada.Args[indice].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			id, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for idioma, cat := range catalogos {
+				if _, ok := cat[id]; !ok {
+					t.Errorf("%s: %q fora do catálogo %s", fset.Position(lit.Pos()), id, idioma)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Um erro criado antes de o idioma ser escolhido sai no idioma escolhido
+func TestTextosErroTraduzidoAoExibir(t *testing.T) {
+	anterior := idiomaAtual
+	t.Cleanup(func() { idiomaAtual = anterior })
+
+	idiomaAtual = IdiomaPortugues
+	teclas := configuracoesPadrao().Teclas
+	teclas[AcaoSalvar] = append(teclas[AcaoSalvar], teclas[AcaoPausar][0])
+	erros := []error{
+		teclasValidar(teclas),
+		textosErro("erro.metadado", "@portal a", textosErro("erro.metadado_portal")),
+	}
+	idiomaAtual = IdiomaIngles
+	esperados := []string{"conflicting keys: ", "invalid metadata \"@portal a\": expected"}
+	for i, err := range erros {
+		if err == nil || !strings.HasPrefix(err.Error(), esperados[i]) {
+			t.Errorf("erro %d = %v, esperado começando com %q", i, err, esperados[i])
+		}
+	}
+	if texto := erros[0].Error(); !strings.Contains(texto, traduzir("acao.salvar")) || !strings.Contains(texto, traduzir("acao.pausar")) {
+		t.Errorf("conflito sem os nomes das ações: %q", texto)
+	}
+}
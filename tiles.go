@@ -0,0 +1,341 @@
+// tiles.go - Registro dos tipos de tile: aparência, passagem, visão, barulho e comportamentos
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Arquivo de definições lido na inicialização, se existir
+const ArquivoTilesPadrao = "tiles.json"
+
+// TipoTile reúne tudo o que o jogo sabe sobre um tipo de tile. Um tile novo é
+// registrado em tilesPadrao ou declarado no arquivo de definições.
+type TipoTile struct {
+	Nome     string
+	Elemento *Elemento // símbolo e cores; aponta para a var do elemento nos tipos do jogo
+
+	Passavel      bool    // personagem e monstros podem entrar
+	BloqueiaVisao bool    // impede a visão na neblina de guerra
+	Abafamento    float64 // fração do barulho abafada quando o personagem pisa no tile (0 a 1)
+	Interativo    bool    // recebe identificador com "@ x y id"
+	Marcador      bool    // marca no arquivo de algo desenhado à parte; não fica na grade
+	Paleta        bool    // aparece na paleta do editor
+	Copiavel      bool    // pode ser a base de um tipo novo em "como"
+
+	// Registra no jogo o tile lido do arquivo de mapa e retorna o elemento da grade
+	AoCarregar func(jogo *Jogo, x, y int, elem Elemento) Elemento
+	// Coleta o item sob o personagem; retorna se ele foi para o inventário
+	Coletar func(jogo *Jogo) bool
+	// O personagem entrou no tile, vindo de anterior
+	AoEntrar func(jogo *Jogo, anterior Position)
+	// O monstro entrou no tile
+	AoEntrarMonstro func(jogo *Jogo)
+	// O personagem interagiu com o tile em (x, y)
+	AoInteragir func(jogo *Jogo, x, y int)
+}
+
+// Tipos registrados, na ordem da paleta do editor, e o índice pelo símbolo
+var (
+	tiposTile       []*TipoTile
+	tiposPorSimbolo map[rune]*TipoTile
+)
+
+func init() {
+	tilesRegistrar(tilesPadrao())
+}
+
+// Tipos de tile do jogo. Só os copiáveis servem de base em "como": os
+// comportamentos dos outros (blocos, portais, jatos, portas, a saída) comparam
+// o elemento exato da grade ou o trocam pelo elemento original, e não
+// funcionariam com o símbolo de outro tipo.
+func tilesPadrao() []*TipoTile {
+	return []*TipoTile{
+		{Nome: "vazio", Elemento: &Vazio, Passavel: true, Paleta: true, Copiavel: true},
+		{Nome: "parede", Elemento: &Parede, BloqueiaVisao: true, Paleta: true, Copiavel: true},
+		{Nome: "vegetacao", Elemento: &Vegetacao, Passavel: true, Abafamento: 0.5, Paleta: true, Copiavel: true},
+		{Nome: "personagem", Elemento: &Personagem, Marcador: true, Paleta: true, AoCarregar: jogoCarregarPersonagem},
+		{Nome: "monstro", Elemento: &Inimigo, Marcador: true, Paleta: true, AoCarregar: jogoCarregarMonstro},
+		{Nome: "invisibilidade", Elemento: &InvisibilityItem, Passavel: true, Paleta: true,
+			AoCarregar: jogoCarregarInvisibilidade, Coletar: ConsumirItemInvisibilidade},
+		{Nome: "estrela", Elemento: &StarElementVisible, Passavel: true, Paleta: true,
+			AoCarregar: jogoCarregarEstrela, Coletar: ConsumirItemEstrela},
+		{Nome: "chave", Elemento: &Chave, Passavel: true, Interativo: true, Paleta: true, Coletar: ConsumirItemChave},
+		{Nome: "porta_trancada", Elemento: &PortaTrancada, BloqueiaVisao: true, Interativo: true, Paleta: true,
+			AoInteragir: personagemAbrirPorta},
+		{Nome: "porta_aberta", Elemento: &PortaAberta, Passavel: true, Interativo: true, Paleta: true},
+		{Nome: "alavanca_desligada", Elemento: &AlavancaDesligada, Interativo: true, Paleta: true,
+			AoInteragir: personagemAcionarAlavanca},
+		{Nome: "alavanca_ligada", Elemento: &AlavancaLigada, Interativo: true, Paleta: true,
+			AoInteragir: personagemAcionarAlavanca},
+		{Nome: "portao_fechado", Elemento: &PortaoFechado, BloqueiaVisao: true, Interativo: true, Paleta: true},
+		{Nome: "portao_aberto", Elemento: &PortaoAberto, Passavel: true, Interativo: true, Paleta: true},
+		{Nome: "bloco", Elemento: &Bloco, Paleta: true, AoCarregar: jogoCarregarBloco},
+		{Nome: "placa", Elemento: &PlacaPressao, Passavel: true, Interativo: true, Paleta: true},
+		{Nome: "bloco_sobre_placa", Elemento: &BlocoSobrePlaca, Interativo: true, Paleta: true,
+			AoCarregar: jogoCarregarBloco},
+		{Nome: "espinhos", Elemento: &Espinhos, Passavel: true, Paleta: true, Copiavel: true,
+			AoEntrar: personagemPisarEspinhos},
+		{Nome: "buraco", Elemento: &Buraco, Passavel: true, Paleta: true, Copiavel: true,
+			AoEntrar: personagemCairNoBuraco, AoEntrarMonstro: jogoMonstroCairNoBuraco},
+		{Nome: "lama", Elemento: &Lama, Passavel: true, Abafamento: 0.25, Paleta: true, Copiavel: true,
+			AoEntrar: personagemAtolar, AoEntrarMonstro: jogoMonstroAtolar},
+		{Nome: "jato", Elemento: &JatoApagado, Passavel: true, Paleta: true, AoCarregar: jogoCarregarJato},
+		{Nome: "jato_aceso", Elemento: &JatoAceso, Passavel: true, AoCarregar: jogoCarregarJato,
+			AoEntrar: personagemEntrarNasChamas, AoEntrarMonstro: jogoMonstroQueimar},
+		{Nome: "portal", Elemento: &Portal, Passavel: true, Interativo: true, Paleta: true,
+			AoEntrar:        func(jogo *Jogo, _ Position) { personagemAtravessarPortal(jogo) },
+			AoEntrarMonstro: jogoAtravessarPortalMonstro},
+		{Nome: "saida", Elemento: &Saida, Passavel: true, Paleta: true,
+			AoEntrar: func(jogo *Jogo, _ Position) { personagemVerificarSaida(jogo) }},
+	}
+}
+
+// Acrescenta tipos ao registro e refaz o índice pelo símbolo
+func tilesRegistrar(tipos []*TipoTile) {
+	tiposTile = append(tiposTile, tipos...)
+	tiposPorSimbolo = make(map[rune]*TipoTile)
+	for _, t := range tiposTile {
+		tiposPorSimbolo[t.Elemento.simbolo] = t
+	}
+}
+
+// Tipo do tile na grade; símbolos sem tipo, como a estrela invisível, contam como vazio
+func tileTipo(elem Elemento) *TipoTile {
+	if t, ok := tiposPorSimbolo[elem.simbolo]; ok {
+		return t
+	}
+	return tiposPorSimbolo[Vazio.simbolo]
+}
+
+func tilePorNome(nome string) *TipoTile {
+	for _, t := range tiposTile {
+		if t.Nome == nome {
+			return t
+		}
+	}
+	return nil
+}
+
+// Elemento guardado na grade para um símbolo do arquivo de mapa. Tiles
+// interativos sem metadados usam o identificador padrão; símbolos
+// desconhecidos viram vazio.
+func tilesCarregar(jogo *Jogo, simbolo rune, x, y int) Elemento {
+	t, ok := tiposPorSimbolo[simbolo]
+	if !ok {
+		return Vazio
+	}
+	if t.Interativo {
+		pos := Position{X: x, Y: y}
+		if _, ok := jogo.Interativos[pos]; !ok {
+			jogo.Interativos[pos] = &Interativo{ID: IdentificadorPadrao}
+		}
+	}
+	if t.AoCarregar != nil {
+		return t.AoCarregar(jogo, x, y, *t.Elemento)
+	}
+	return *t.Elemento
+}
+
+// O personagem entrou no tile sob ele: conta o movimento para a recarga dos
+// portais, coleta o item e aplica o comportamento do tipo. Retorna se algum
+// item foi para o inventário.
+func personagemEntrarTile(jogo *Jogo, anterior Position) bool {
+	jogoRecarregarPortal(jogo, EntidadePersonagem)
+	t := tileTipo(jogo.UltimoVisitado)
+	coletou := t.Coletar != nil && t.Coletar(jogo)
+	if t.AoEntrar != nil {
+		t.AoEntrar(jogo, anterior)
+	}
+	return coletou
+}
+
+// O monstro entrou no tile da posição atual dele
+func jogoMonstroEntrarTile(jogo *Jogo) {
+	m := jogo.Monstro
+	if m == nil || jogoMonstroCapturado(jogo) {
+		return
+	}
+	elem, _ := jogoElementoEm(jogo, m.current_position.X, m.current_position.Y)
+	if t := tileTipo(elem); t.AoEntrarMonstro != nil {
+		t.AoEntrarMonstro(jogo)
+	}
+}
+
+// Definição de um tile no arquivo; os campos omitidos mantêm o valor do tipo
+// existente ou, para um tipo novo, do tipo indicado em "como" (vazio se omitido)
+type DefinicaoTile struct {
+	Nome          string   `json:"nome"`
+	Simbolo       string   `json:"simbolo,omitempty"`
+	Cor           string   `json:"cor,omitempty"`
+	Fundo         string   `json:"fundo,omitempty"`
+	Passavel      *bool    `json:"passavel,omitempty"`
+	BloqueiaVisao *bool    `json:"bloqueia_visao,omitempty"`
+	Abafamento    *float64 `json:"abafamento,omitempty"`
+	Paleta        *bool    `json:"paleta,omitempty"`
+	Como          string   `json:"como,omitempty"`
+}
+
+// Nomes de cores aceitos no arquivo de definições
+var coresPorNome = map[string]Cor{
+	"padrao":   CorPadrao,
+	"preto":    termbox.ColorBlack,
+	"vermelho": termbox.ColorRed,
+	"verde":    termbox.ColorGreen,
+	"amarelo":  termbox.ColorYellow,
+	"azul":     termbox.ColorBlue,
+	"magenta":  termbox.ColorMagenta,
+	"ciano":    termbox.ColorCyan,
+	"branco":   termbox.ColorWhite,
+	"cinza":    termbox.ColorDarkGray,
+}
+
+// Lê o arquivo de definições de tiles. Sem o arquivo padrão, nada muda.
+func tilesCarregarDefinicoes(nome string) error {
+	dados, err := os.ReadFile(nome)
+	if os.IsNotExist(err) && nome == ArquivoTilesPadrao {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var arquivo struct {
+		Tiles []DefinicaoTile `json:"tiles"`
+	}
+	if err := json.Unmarshal(dados, &arquivo); err != nil {
+		return textosErro("erro.tiles_arquivo", nome, err)
+	}
+	for _, def := range arquivo.Tiles {
+		if err := tilesAplicarDefinicao(def); err != nil {
+			return textosErro("erro.tiles_tile", nome, def.Nome, err)
+		}
+	}
+	return nil
+}
+
+// Ajusta um tipo existente ou registra um novo. O símbolo dos tipos existentes
+// não muda, porque é o formato dos mapas e dos saves.
+func tilesAplicarDefinicao(def DefinicaoTile) error {
+	if def.Nome == "" {
+		return textosErro("erro.tile_sem_nome")
+	}
+	var simbolo rune
+	if def.Simbolo != "" {
+		r := []rune(def.Simbolo)
+		if len(r) != 1 {
+			return textosErro("erro.tile_simbolo", def.Simbolo)
+		}
+		simbolo = r[0]
+	}
+
+	t := tilePorNome(def.Nome)
+	if t != nil {
+		if def.Como != "" {
+			return textosErro("erro.tile_como_existente")
+		}
+		if simbolo != 0 && simbolo != t.Elemento.simbolo {
+			return textosErro("erro.tile_simbolo_fixo")
+		}
+	} else {
+		if simbolo == 0 {
+			return textosErro("erro.tile_sem_simbolo")
+		}
+		if outro, ok := tiposPorSimbolo[simbolo]; ok {
+			return textosErro("erro.tile_simbolo_usado", def.Simbolo, outro.Nome)
+		}
+		base := tilePorNome("vazio")
+		if def.Como != "" {
+			if base = tilePorNome(def.Como); base == nil {
+				return textosErro("erro.tile_como_desconhecido", def.Como)
+			}
+			if !base.Copiavel {
+				return textosErro("erro.tile_como_proibido", def.Como)
+			}
+		}
+		novo := *base
+		elem := Elemento{simbolo, base.Elemento.cor, base.Elemento.corFundo}
+		novo.Nome, novo.Elemento, novo.Paleta, novo.Marcador = def.Nome, &elem, true, false
+		t = &novo
+		tilesRegistrar([]*TipoTile{t})
+	}
+
+	if def.Cor != "" {
+		cor, ok := coresPorNome[strings.ToLower(def.Cor)]
+		if !ok {
+			return textosErro("erro.tile_cor", def.Cor)
+		}
+		t.Elemento.cor = cor
+	}
+	if def.Fundo != "" {
+		cor, ok := coresPorNome[strings.ToLower(def.Fundo)]
+		if !ok {
+			return textosErro("erro.tile_cor", def.Fundo)
+		}
+		t.Elemento.corFundo = cor
+	}
+	if def.Passavel != nil {
+		t.Passavel = *def.Passavel
+	}
+	if def.BloqueiaVisao != nil {
+		t.BloqueiaVisao = *def.BloqueiaVisao
+	}
+	if def.Abafamento != nil {
+		if *def.Abafamento < 0 || *def.Abafamento > 1 {
+			return textosErro("erro.tile_abafamento", *def.Abafamento)
+		}
+		t.Abafamento = *def.Abafamento
+	}
+	if def.Paleta != nil {
+		t.Paleta = *def.Paleta
+	}
+	return nil
+}
+
+// Hooks de carregamento dos tipos do jogo
+
+func jogoCarregarPersonagem(jogo *Jogo, x, y int, _ Elemento) Elemento {
+	jogo.PosX, jogo.PosY = x, y // registra a posição inicial do personagem
+	jogo.InicioX, jogo.InicioY = x, y
+	return Vazio
+}
+
+// O monstro é desenhado separadamente; só o primeiro do mapa é criado
+func jogoCarregarMonstro(jogo *Jogo, x, y int, _ Elemento) Elemento {
+	if jogo.Monstro == nil {
+		jogo.Monstro = &Monster{
+			current_position: Position{X: x, Y: y},
+			state:            Patrolling,
+			destiny_position: Position{X: x + 5, Y: y + 5},
+			id:               "monster_1",
+			origin:           Position{X: x, Y: y},
+			rng:              rand.New(rand.NewSource(jogo.Semente)),
+		}
+		jogo.Monstro.publishSnapshot()
+	}
+	return Vazio
+}
+
+func jogoCarregarInvisibilidade(jogo *Jogo, x, y int, elem Elemento) Elemento {
+	jogo.InvisibilityItems = append(jogo.InvisibilityItems, &Invisibility{X: x, Y: y})
+	return elem
+}
+
+func jogoCarregarEstrela(jogo *Jogo, x, y int, elem Elemento) Elemento {
+	jogo.EstrelasTotal++
+	return elem
+}
+
+func jogoCarregarBloco(jogo *Jogo, x, y int, elem Elemento) Elemento {
+	jogo.BlocosIniciais = append(jogo.BlocosIniciais, Position{X: x, Y: y})
+	return elem
+}
+
+// Todo jato começa apagado
+func jogoCarregarJato(jogo *Jogo, x, y int, _ Elemento) Elemento {
+	jogo.Jatos = append(jogo.Jatos, Position{X: x, Y: y})
+	return JatoApagado
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+// As definições mudam o registro global e as vars dos elementos; o teste
+// devolve tudo como estava ao terminar
+func tilesRestaurarAoFim(t *testing.T) {
+	t.Helper()
+	tipos := append([]*TipoTile(nil), tiposTile...)
+	copias := make([]TipoTile, len(tipos))
+	elementos := make([]Elemento, len(tipos))
+	for i, tipo := range tipos {
+		copias[i], elementos[i] = *tipo, *tipo.Elemento
+	}
+	t.Cleanup(func() {
+		for i, tipo := range tipos {
+			*tipo = copias[i]
+			*tipo.Elemento = elementos[i]
+		}
+		tiposTile = tipos
+		tilesRegistrar(nil)
+	})
+}
+
+func TestTilesRegistro(t *testing.T) {
+	nomes := make(map[string]bool)
+	for _, tipo := range tiposTile {
+		if nomes[tipo.Nome] {
+			t.Errorf("nome %q registrado duas vezes", tipo.Nome)
+		}
+		nomes[tipo.Nome] = true
+		if tiposPorSimbolo[tipo.Elemento.simbolo] != tipo {
+			t.Errorf("símbolo %q de %s aponta para outro tipo", tipo.Elemento.simbolo, tipo.Nome)
+		}
+		if tilePorNome(tipo.Nome) != tipo {
+			t.Errorf("tilePorNome(%q) não encontra o tipo", tipo.Nome)
+		}
+	}
+	if tilePorNome("inexistente") != nil {
+		t.Error("tilePorNome encontrou um tipo inexistente")
+	}
+
+	casos := []struct {
+		nome       string
+		simbolo    rune
+		esperado   Elemento
+		interativo bool
+	}{
+		{"parede", Parede.simbolo, Parede, false},
+		{"símbolo desconhecido vira vazio", '?', Vazio, false},
+		{"jato começa apagado", JatoAceso.simbolo, JatoApagado, false},
+		{"personagem fica fora da grade", Personagem.simbolo, Vazio, false},
+		{"porta recebe o identificador padrão", PortaTrancada.simbolo, PortaTrancada, true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			jogo := jogoNovo()
+			if got := tilesCarregar(&jogo, c.simbolo, 2, 3); got != c.esperado {
+				t.Errorf("tilesCarregar(%q) = %q, esperado %q", c.simbolo, got.simbolo, c.esperado.simbolo)
+			}
+			it, ok := jogo.Interativos[Position{X: 2, Y: 3}]
+			if ok != c.interativo || (ok && it.ID != IdentificadorPadrao) {
+				t.Errorf("interativo = %+v, %v", it, ok)
+			}
+		})
+	}
+	if tileTipo(StarElementInvisible) != tilePorNome("vazio") {
+		t.Error("símbolo sem tipo não conta como vazio")
+	}
+}
+
+func TestTilesCarregarDefinicoes(t *testing.T) {
+	tilesRestaurarAoFim(t)
+	nome := filepath.Join(t.TempDir(), "tiles.json")
+	definicoes := `{"tiles": [
+		{"nome": "vegetacao", "cor": "azul", "abafamento": 0.9},
+		{"nome": "areia", "simbolo": "░", "como": "lama", "fundo": "Amarelo", "abafamento": 0.1},
+		{"nome": "grade", "simbolo": "#", "bloqueia_visao": false, "paleta": false, "como": "parede"}
+	]}`
+	if err := os.WriteFile(nome, []byte(definicoes), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tilesCarregarDefinicoes(nome); err != nil {
+		t.Fatal(err)
+	}
+
+	// Um tipo existente muda no registro e na var do elemento
+	vegetacao := tilePorNome("vegetacao")
+	if Vegetacao.cor != termbox.ColorBlue || vegetacao.Abafamento != 0.9 || !vegetacao.Passavel {
+		t.Errorf("vegetação = %+v, cor %v", vegetacao, Vegetacao.cor)
+	}
+
+	// Um tipo novo herda o comportamento do tipo em "como"
+	areia := tiposPorSimbolo['░']
+	if areia == nil || areia.Nome != "areia" {
+		t.Fatalf("areia não registrada pelo símbolo: %+v", areia)
+	}
+	lama := tilePorNome("lama")
+	if !areia.Passavel || areia.AoEntrar == nil || areia.AoEntrarMonstro == nil || areia.Abafamento != 0.1 {
+		t.Errorf("areia não herdou da lama: %+v", areia)
+	}
+	if areia.Elemento.cor != lama.Elemento.cor || areia.Elemento.corFundo != termbox.ColorYellow {
+		t.Errorf("cores da areia = %v/%v", areia.Elemento.cor, areia.Elemento.corFundo)
+	}
+	if !areia.Paleta || areia.Marcador {
+		t.Errorf("areia com paleta %v, marcador %v", areia.Paleta, areia.Marcador)
+	}
+	grade := tilePorNome("grade")
+	if grade == nil || grade.Passavel || grade.BloqueiaVisao || grade.Paleta {
+		t.Errorf("grade = %+v", grade)
+	}
+	if lama.Abafamento != 0.25 || Lama.corFundo != CorPadrao {
+		t.Error("definir a areia mudou a lama")
+	}
+
+	// O símbolo novo é lido dos mapas
+	jogo := jogoNovo()
+	if got := tilesCarregar(&jogo, '░', 1, 1); got.simbolo != '░' {
+		t.Errorf("mapa com areia carregou %q", got.simbolo)
+	}
+}
+
+func TestTilesArquivoDefinicoes(t *testing.T) {
+	tilesRestaurarAoFim(t)
+	dir := t.TempDir()
+	invalido := filepath.Join(dir, "invalido.json")
+	if err := os.WriteFile(invalido, []byte(`{"tiles": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	comErro := filepath.Join(dir, "erro.json")
+	if err := os.WriteFile(comErro, []byte(`{"tiles": [{"nome": "lama", "cor": "roxo"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	anterior, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(anterior)
+
+	casos := []struct {
+		nome string
+		id   string // identificador do erro; vazio se não houver erro
+	}{
+		{ArquivoTilesPadrao, ""}, // o arquivo padrão é opcional
+		{"outro.json", "os"},
+		{invalido, "erro.tiles_arquivo"},
+		{comErro, "erro.tiles_tile"},
+	}
+	for _, c := range casos {
+		err := tilesCarregarDefinicoes(c.nome)
+		erroTexto, traduzido := err.(ErroTexto)
+		switch {
+		case c.id == "" && err != nil:
+			t.Errorf("%s: %v", c.nome, err)
+		case c.id == "os" && (err == nil || traduzido):
+			t.Errorf("%s: erro %v, esperado o da leitura do arquivo", c.nome, err)
+		case c.id != "" && c.id != "os" && (!traduzido || erroTexto.ID != c.id):
+			t.Errorf("%s: erro %v, esperado %s", c.nome, err, c.id)
+		}
+	}
+}
+
+func TestTilesDefinicaoInvalida(t *testing.T) {
+	verdadeiro := func() *bool { v := true; return &v }
+	abafamento := 1.5
+	casos := []struct {
+		nome string
+		def  DefinicaoTile
+		id   string
+	}{
+		{"sem nome", DefinicaoTile{Simbolo: "x"}, "erro.tile_sem_nome"},
+		{"símbolo com dois caracteres", DefinicaoTile{Nome: "novo", Simbolo: "ab"}, "erro.tile_simbolo"},
+		{"tipo novo sem símbolo", DefinicaoTile{Nome: "novo", Passavel: verdadeiro()}, "erro.tile_sem_simbolo"},
+		{"símbolo já usado", DefinicaoTile{Nome: "novo", Simbolo: string(Parede.simbolo)}, "erro.tile_simbolo_usado"},
+		{"símbolo de um tipo existente", DefinicaoTile{Nome: "parede", Simbolo: "x"}, "erro.tile_simbolo_fixo"},
+		{"cor desconhecida", DefinicaoTile{Nome: "parede", Cor: "roxo"}, "erro.tile_cor"},
+		{"fundo desconhecido", DefinicaoTile{Nome: "parede", Fundo: "roxo"}, "erro.tile_cor"},
+		{"abafamento fora do intervalo", DefinicaoTile{Nome: "lama", Abafamento: &abafamento}, "erro.tile_abafamento"},
+		{"como em tipo existente", DefinicaoTile{Nome: "parede", Como: "lama"}, "erro.tile_como_existente"},
+		{"como desconhecido", DefinicaoTile{Nome: "novo", Simbolo: "x", Como: "lava"}, "erro.tile_como_desconhecido"},
+	}
+	// Os tipos cujo comportamento depende do elemento exato não servem de base
+	for _, tipo := range tilesPadrao() {
+		if !tipo.Copiavel {
+			casos = append(casos, struct {
+				nome string
+				def  DefinicaoTile
+				id   string
+			}{"como " + tipo.Nome, DefinicaoTile{Nome: "novo", Simbolo: "x", Como: tipo.Nome}, "erro.tile_como_proibido"})
+		}
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			tilesRestaurarAoFim(t)
+			total := len(tiposTile)
+			err := tilesAplicarDefinicao(c.def)
+			if e, ok := err.(ErroTexto); !ok || e.ID != c.id {
+				t.Fatalf("erro %v, esperado %s", err, c.id)
+			}
+			if len(tiposTile) != total {
+				t.Error("definição inválida registrou um tipo")
+			}
+		})
+	}
+
+	// Os copiáveis são aceitos
+	for _, tipo := range tilesPadrao() {
+		if tipo.Copiavel {
+			tilesRestaurarAoFim(t)
+			if err := tilesAplicarDefinicao(DefinicaoTile{Nome: "novo_" + tipo.Nome, Simbolo: "x", Como: tipo.Nome}); err != nil {
+				t.Errorf("como %s: %v", tipo.Nome, err)
+			}
+			tiposTile = tiposTile[:len(tiposTile)-1]
+			tilesRegistrar(nil)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 // types.go - Definições de tipos para elementos especiais
 package main
 
+import (
+	"math/rand"
+	"sync"
+)
+
 type Position struct {
 	X, Y int
 }
@@ -11,16 +16,30 @@ type MonsterState int
 const (
 	Hunting    MonsterState = iota 
 	Patrolling                 
+	Fleeing    // assustado pelo poder do personagem
+	Caught     // capturado, aguardando para reaparecer na origem
 )
 
 // Structs dos elementos especiais
 type Monster struct {
-	current_position Position     // Posição atual do monster
+	current_position Position     // Posição atual do monster, mudada só pelo jogo (definirPosicao)
 	shift_count      int          // Contador para movimento a cada 2 turnos
 	destiny_position Position     // Posição de destino (patrulha)
 	last_seen        Position     // Última posição vista do jogador
 	state            MonsterState // Estado atual (hunting/patrolling)
 	id               string       // ID único do monster
+	origin           Position     // Posição inicial, onde reaparece depois de capturado
+	flee_path        []Position   // Rota de fuga calculada pelo jogo
+	route            []Position   // Rota até route_target calculada pelo jogo, que conhece paredes e portais
+	route_target     Position     // Destino da rota recebida
+	route_asked      Position     // Destino da última rota pedida ao jogo
+	stuck_ticks      int          // Tiques que ainda fica parado na lama
+	respawning       bool         // Pediu ao jogo para voltar à origem e ainda não foi atendido
+	epoca            int          // Última mudança de estado imposta pelo jogo que o monster recebeu
+	rng              *rand.Rand   // gerador de números aleatórios do monster
+
+	mu        sync.Mutex      // protege publicado e as mudanças de current_position
+	publicado MonsterSnapshot // cópia do estado lida pelo jogo
 }
 
 type StarBonus struct {
@@ -28,7 +47,8 @@ type StarBonus struct {
 }
 
 type Invisibility struct {
-	X, Y int // Posição do item de invisibilidade
+	X, Y    int                // Posição do item de invisibilidade
+	Coletas chan PlayerCollect `json:"-"` // coleta deste item, entregue pelo jogo
 }
 
 type GameEvent struct {
@@ -42,9 +62,25 @@ type MonsterMoveData struct {
 	MonsterID  string 
 }
 
+// Pedido de rota do monstro ao jogo, e a rota devolvida no alerta "route"
+type MonsterRouteData struct {
+	MonsterID string
+	Para      Position
+	Caminho   []Position
+}
+
+// Mudança de estado do monstro, para o HUD saber se ele está caçando. A época
+// é a da última mudança imposta pelo jogo que o monstro tinha recebido.
+type MonsterStateData struct {
+	MonsterID string
+	State     MonsterState
+	Epoca     int
+}
+
 type PlayerAlert struct {
-	Type string     
-	Data interface{} 
+	Type  string     
+	Data  interface{} 
+	Epoca int // época das mudanças de estado impostas pelo jogo; 0 nos demais alertas
 }
 
 type PlayerState struct {